@@ -11,9 +11,12 @@ import (
 )
 
 var analyzeCmd = &cobra.Command{
-	Use:   "analyze",
+	Use:   "analyze [revision-range] [-- <pathspec>...]",
 	Short: "Analyze the current Git repository",
-	Long:  `Perform a detailed analysis of the Git repository, including commit statistics and author contributions.`,
+	Long: `Perform a detailed analysis of the Git repository, including commit statistics and author contributions.
+
+An optional revision range (e.g. v1.2.0..v1.3.0, main...feature, HEAD~10) selects the commits to analyze.`,
+	Args: revisionAndPathArgs,
 	Run: func(cmd *cobra.Command, args []string) {
 		var commits []*object.Commit
 		currentDir, _ := os.Getwd()
@@ -22,39 +25,60 @@ var analyzeCmd = &cobra.Command{
 			fmt.Println("❌ Not a Git repository.")
 			return
 		}
-		num, _ := cmd.Flags().GetInt("number")
+		num := intSetting(cmd, "number", "number")
 		unique, _ := cmd.Flags().GetBool("unique")
-		base, _ := cmd.Flags().GetString("base")
 		if num < 1 {
 			num = 5
 		}
-		// If unique mode, try to auto-detect base if not explicitly set
-		if unique && (base == "" || base == "auto") {
-			autoBase, err := repo.DetectDefaultBranch()
-			if err != nil {
-				fmt.Printf("⚠️  Could not auto-detect default branch: %v\n", err)
+		opts, err := listOptionsFromFlags(cmd, repo, args, num, "number")
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			return
+		}
+		rangeSpec := revisionArg(cmd, args)
+		if unique && rangeSpec != "" {
+			fmt.Println("❌ --unique cannot be combined with a revision range")
+			return
+		}
+
+		// In unique mode, auto-detect the base unless one was given
+		var base string
+		if unique {
+			if base, err = baseFromFlags(cmd, repo); err != nil {
+				fmt.Printf("⚠️  %v\n", err)
 				return
 			}
-			base = autoBase
 		}
 
-		if unique {
-			commits, err = repo.ListUniqueCommits(base, num)
+		if rangeSpec != "" {
+			commits, err = repo.ListRangeCommits(rangeSpec, opts)
+			if err != nil {
+				fmt.Printf("❌ Error listing commits in range '%s': %v\n", rangeSpec, err)
+				return
+			}
+			fmt.Printf("🔎 Showing %d commits in range '%s'%s:\n\n", len(commits), rangeSpec, describeListOptions(opts))
+		} else if unique {
+			commits, err = repo.ListUniqueCommitsWithOptions(base, opts)
 			if err != nil {
 				fmt.Printf("❌ Error listing unique commits (base=%s): %v\n", base, err)
 				return
 			}
-			fmt.Printf("🔎 Showing last %d commits unique to branch '%s' (vs base '%s'):\n\n", len(commits), repo.CurrentBranchName(), base)
+			fmt.Printf("🔎 Showing last %d commits unique to branch '%s' (vs base '%s')%s:\n\n", len(commits), repo.CurrentBranchName(), base, describeListOptions(opts))
 		} else {
-			commits, err = repo.ListCommits(num)
+			commits, err = repo.ListCommitsWithOptions(opts)
 			if err != nil {
 				fmt.Printf("❌ Error listing commits: %v\n", err)
 				return
 			}
-			fmt.Printf("🔎 Showing last %d commits on branch '%s':\n\n", len(commits), repo.CurrentBranchName())
+			fmt.Printf("🔎 Showing last %d commits on branch '%s'%s:\n\n", len(commits), repo.CurrentBranchName(), describeListOptions(opts))
 		}
 
-		summarize, err := repo.ListCommitSummarize(commits)
+		diffOpts, err := diffOptionsFromFlags(cmd, args)
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			return
+		}
+		summarize, err := repo.ListCommitSummarize(commits, diffOpts)
 		if err != nil {
 			fmt.Printf("❌ Error listing commit summarizes: %v\n", err)
 			return
@@ -68,7 +92,9 @@ var analyzeCmd = &cobra.Command{
 
 func init() {
 	rootCmd.AddCommand(analyzeCmd)
+	addCommitFilterFlags(analyzeCmd)
+	addCacheFlag(analyzeCmd)
 	analyzeCmd.Flags().IntP("number", "n", 5, "Number of commits to analyze")
 	analyzeCmd.Flags().Bool("unique", false, "Show only commits unique to this branch (compared to main)")
-	analyzeCmd.Flags().String("base", "main", "Base branch name for unique commit comparison")
+	analyzeCmd.Flags().String("base", "auto", "Base for --unique: a branch, remote branch (origin/main), tag or SHA (default: auto-detect from origin/HEAD, then main/master)")
 }
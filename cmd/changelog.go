@@ -0,0 +1,246 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/frfahim/gitstory/internal/changelog"
+	"github.com/frfahim/gitstory/internal/git"
+	"github.com/frfahim/gitstory/internal/llm"
+	"github.com/frfahim/gitstory/internal/types"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/spf13/cobra"
+)
+
+var changelogCmd = &cobra.Command{
+	Use:   "changelog",
+	Short: "Generate CHANGELOG.md sections from your tags and commits",
+	Long: `Generate Keep a Changelog (https://keepachangelog.com) sections for each
+release tag, and for the commits since the last tag, from Conventional Commits.
+
+Releases already in the changelog are left as they are, manual edits included:
+only the missing sections are generated and inserted in place. The [Unreleased]
+section is generated too: it is regenerated when new commits land, and removed
+once a release covers its commits. Without --write the changes are printed instead.
+
+Examples:
+  gitstory changelog                        # Preview the sections missing from CHANGELOG.md
+  gitstory changelog --write                # Insert them into CHANGELOG.md
+  gitstory changelog --release 1.4.0 --write # Release the unreleased commits as 1.4.0
+  gitstory changelog --ai --write           # Let the LLM rewrite entries for users`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runChangelog(cmd)
+	},
+}
+
+func runChangelog(cmd *cobra.Command) error {
+	file, _ := cmd.Flags().GetString("file")
+	write, _ := cmd.Flags().GetBool("write")
+	release, _ := cmd.Flags().GetString("release")
+	useAI, _ := cmd.Flags().GetBool("ai")
+
+	currentDir, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+	repo, err := git.OpenRepository(currentDir)
+	if err != nil {
+		return fmt.Errorf("❌ Not a Git repository: %w", err)
+	}
+
+	existing, err := os.ReadFile(file)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to read %s: %w", file, err)
+	}
+
+	releases, commitsOf, err := changelogReleases(repo, release)
+	if err != nil {
+		return err
+	}
+
+	// Only missing releases and a stale [Unreleased] section are generated
+	pending := changelog.Pending(string(existing), releases)
+	rendered := map[string]string{}
+	for _, r := range pending {
+		rendered[r.Version] = changelog.Render(r)
+	}
+	if useAI && len(pending) > 0 {
+		if err := rewriteChangelog(cmd, repo, pending, commitsOf, rendered); err != nil {
+			return err
+		}
+	}
+
+	// Update places each new section by the releases around it, so it needs them all
+	updated, changes := changelog.Update(string(existing), releases, func(r changelog.Release) string {
+		return rendered[r.Version]
+	})
+	if changes.Empty() {
+		fmt.Printf("✅ %s is up to date\n", file)
+		return nil
+	}
+
+	if !write {
+		for _, version := range append(changes.Added, changes.Updated...) {
+			fmt.Println(rendered[version])
+		}
+		for _, version := range changes.Removed {
+			fmt.Printf("🗑️ [%s] would be removed: its commits are released now\n", version)
+		}
+		fmt.Printf("ℹ️ Changes for %s (%s); pass --write to apply them\n", file, describeChangelogChanges(changes))
+		return nil
+	}
+	if err := os.WriteFile(file, []byte(updated), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", file, err)
+	}
+	fmt.Printf("💾 Updated %s: %s\n", file, describeChangelogChanges(changes))
+	return nil
+}
+
+// describeChangelogChanges summarizes changes, e.g. "added 1.2.0, removed [Unreleased]"
+func describeChangelogChanges(changes changelog.Changes) string {
+	var parts []string
+	if len(changes.Added) > 0 {
+		parts = append(parts, "added "+strings.Join(changes.Added, ", "))
+	}
+	if len(changes.Updated) > 0 {
+		parts = append(parts, "regenerated ["+strings.Join(changes.Updated, "], [")+"]")
+	}
+	if len(changes.Removed) > 0 {
+		parts = append(parts, "removed ["+strings.Join(changes.Removed, "], [")+"]")
+	}
+	return strings.Join(parts, ", ")
+}
+
+// changelogReleases returns a release for each version tag reachable from
+// HEAD and one for the commits since the last of them, newest first, along
+// with each release's commits by version. The commits since the last tag are
+// named version when given, else Unreleased. Releases with nothing to list,
+// such as a second tag on the same commit, are left out.
+func changelogReleases(repo *git.Repository, version string) ([]changelog.Release, map[string][]*object.Commit, error) {
+	tags, err := repo.TagsReachableFrom("HEAD")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var releases []changelog.Release
+	commitsOf := map[string][]*object.Commit{}
+	previous := ""
+	add := func(name string, date time.Time, to string) error {
+		spec := to
+		if previous != "" {
+			spec = previous + ".." + to
+		}
+		commits, err := repo.ListRangeCommits(spec, git.ListOptions{SkipMerges: true})
+		if err != nil {
+			return fmt.Errorf("failed to list commits in %s: %w", spec, err)
+		}
+		data := make([]types.CommitData, len(commits))
+		for i, commit := range commits {
+			data[i] = git.NewCommitData(commit)
+		}
+		if changelog.HasEntries(data) {
+			releases = append([]changelog.Release{{Version: name, Date: date, Commits: data}}, releases...)
+			commitsOf[name] = commits
+		}
+		return nil
+	}
+
+	for _, tag := range tags {
+		name, ok := changelog.ReleaseVersion(tag.Name)
+		if !ok {
+			continue
+		}
+		if err := add(name, tag.Date, tag.Name); err != nil {
+			return nil, nil, err
+		}
+		previous = tag.Name
+	}
+
+	unreleased, date := changelog.Unreleased, time.Time{}
+	if version != "" {
+		name, ok := changelog.ReleaseVersion(version)
+		if !ok {
+			return nil, nil, fmt.Errorf("--release %q is not a version", version)
+		}
+		unreleased, date = name, time.Now()
+	}
+	if err := add(unreleased, date, "HEAD"); err != nil {
+		return nil, nil, err
+	}
+	return releases, commitsOf, nil
+}
+
+// rewriteChangelog replaces the rendered sections with entries the LLM
+// wrote for users. The commits' changed files are sent for context, not
+// their diffs.
+func rewriteChangelog(cmd *cobra.Command, repo *git.Repository, releases []changelog.Release, commitsOf map[string][]*object.Commit, rendered map[string]string) error {
+	userContext := stringSetting(cmd, "context", "context")
+	allContent, _ := cmd.Flags().GetBool("all-content")
+	opts := git.DiffOptions{
+		Merges:          git.MergeSkip,
+		ContentExcludes: contentExcludesFromFlags(cmd),
+		AllContent:      allContent,
+	}
+	providers, err := providersFromFlags(cmd, false)
+	if err != nil {
+		return err
+	}
+	client, err := newClientFromFlags(cmd, providers)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	for _, release := range releases {
+		commits, err := repo.ListCommitSummarize(commitsOf[release.Version], opts)
+		if err != nil {
+			return fmt.Errorf("failed to get changed files: %w", err)
+		}
+		if err := redactFromFlags(cmd, commits); err != nil {
+			return err
+		}
+
+		fmt.Printf("🧠 Writing %s with %s...\n", release.Heading(), joinProviders(client.Providers(), " → "))
+		response, err := client.Summarize(ctx, &llm.SummaryRequest{
+			Commits:     commits,
+			Platform:    llm.Changelog,
+			UserContext: userContext,
+		})
+		if err != nil {
+			return summarizeError(err)
+		}
+		body := strings.TrimSpace(response.Summary)
+		// Models sometimes repeat the release heading despite the instructions
+		if strings.HasPrefix(body, "## ") {
+			_, body, _ = strings.Cut(body, "\n")
+			body = strings.TrimSpace(body)
+		}
+		if body == "" {
+			fmt.Printf("⚠️ %s returned no entries for %s, keeping the generated ones\n", response.Provider, release.Version)
+			continue
+		}
+		rendered[release.Version] = release.Heading() + "\n\n" + body + "\n"
+	}
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(changelogCmd)
+
+	changelogCmd.Flags().String("file", "CHANGELOG.md", "Changelog to update")
+	changelogCmd.Flags().Bool("write", false, "Insert the new sections into the changelog instead of printing them")
+	changelogCmd.Flags().String("release", "", "Version for the commits since the last tag (default: Unreleased)")
+
+	// AI options
+	changelogCmd.Flags().Bool("ai", false, "Have the LLM rewrite the entries of new sections in user-facing language")
+	changelogCmd.Flags().String("context", "", "Additional context about the project for --ai")
+	addProviderFlags(changelogCmd)
+	addRedactFlags(changelogCmd)
+	addContentFlags(changelogCmd)
+}
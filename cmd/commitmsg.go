@@ -0,0 +1,165 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/frfahim/gitstory/internal/conventional"
+	"github.com/frfahim/gitstory/internal/git"
+	"github.com/frfahim/gitstory/internal/llm"
+	"github.com/frfahim/gitstory/internal/types"
+	"github.com/spf13/cobra"
+)
+
+var commitMsgCmd = &cobra.Command{
+	Use:   "commit-msg",
+	Short: "Write a Conventional Commits message for the staged changes",
+	Long: `Have an LLM write a Conventional Commits message for the changes staged for the
+next commit, the same changes git diff --cached shows. With --all it describes
+every change to tracked files instead, like git commit --all; untracked files
+are never included. Nothing is staged or committed.
+
+The message is printed to stdout and progress to stderr, so it can be piped
+into git. With --write it is also saved to .git/COMMIT_EDITMSG for review.
+
+Examples:
+  gitstory commit-msg                        # Message for the staged changes
+  gitstory commit-msg --all                  # Message for all changes to tracked files
+  git commit -m "$(gitstory commit-msg)"     # Commit with the generated message
+  gitstory commit-msg --write && git commit -eF .git/COMMIT_EDITMSG
+  gitstory commit-msg --dry-run              # Show the prompt without calling a provider`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runCommitMsg(cmd)
+	},
+}
+
+func runCommitMsg(cmd *cobra.Command) error {
+	all, _ := cmd.Flags().GetBool("all")
+	write, _ := cmd.Flags().GetBool("write")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	allContent, _ := cmd.Flags().GetBool("all-content")
+	userContext := stringSetting(cmd, "context", "context")
+
+	// stdout is for the message alone
+	cmd.SetOut(os.Stderr)
+	out := cmd.OutOrStdout()
+
+	currentDir, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+	repo, err := git.OpenRepository(currentDir)
+	if err != nil {
+		return fmt.Errorf("❌ Not a Git repository: %w", err)
+	}
+
+	if all {
+		fmt.Fprintln(out, "🔍 Getting changes to tracked files...")
+	} else {
+		fmt.Fprintln(out, "🔍 Getting staged changes...")
+	}
+	details, err := repo.StagedChanges(all, git.DiffOptions{
+		IncludeDiff:     true,
+		ContentExcludes: contentExcludesFromFlags(cmd),
+		AllContent:      allContent,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get changes: %w", err)
+	}
+	if len(details.Files) == 0 {
+		if all {
+			fmt.Fprintln(out, "ℹ️ No changes to tracked files.")
+		} else {
+			fmt.Fprintln(out, "ℹ️ Nothing staged; stage changes with git add, or pass --all")
+		}
+		return nil
+	}
+	fmt.Fprintf(out, "📝 Found %d changed file(s) (+%d -%d)\n", len(details.Files), details.Stats.Additions, details.Stats.Deletions)
+
+	// The changes go to the provider as a single commit without a message
+	changes := []types.CommitData{{Files: details.Files, Stats: details.Stats}}
+
+	// Strip secrets before anything leaves the machine
+	if err := redactFromFlags(cmd, changes); err != nil {
+		return err
+	}
+
+	request := &llm.SummaryRequest{
+		Commits:     changes,
+		Platform:    llm.CommitMessage,
+		UserContext: userContext,
+	}
+	providers, err := providersFromFlags(cmd, dryRun)
+	if err != nil {
+		return err
+	}
+	if dryRun {
+		var rendered []*llm.RenderedPrompt
+		for i, p := range providers {
+			rendered = append(rendered, llm.RenderPrompt(clientConfigFromFlags(cmd, i, p), request))
+		}
+		return displayDryRun(rendered, "")
+	}
+
+	client, err := newClientFromFlags(cmd, providers)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "🧠 Writing the commit message with %s...\n", joinProviders(client.Providers(), " → "))
+	response, err := client.Summarize(context.Background(), request)
+	if err != nil {
+		return summarizeError(err)
+	}
+
+	message := cleanCommitMessage(response.Summary)
+	if message == "" {
+		return fmt.Errorf("%s returned an empty commit message", response.Provider)
+	}
+	if conventional.Parse(message) == nil {
+		fmt.Fprintln(out, "⚠️ The message doesn't follow Conventional Commits; review it before committing")
+	}
+
+	if write {
+		gitDir := repo.GitDir()
+		if gitDir == "" {
+			return fmt.Errorf("--write needs a repository with a .git directory")
+		}
+		path := filepath.Join(gitDir, "COMMIT_EDITMSG")
+		if err := os.WriteFile(path, []byte(message+"\n"), 0644); err != nil {
+			return fmt.Errorf("failed to write the commit message: %w", err)
+		}
+		fmt.Fprintf(out, "💾 Commit message saved to %s; review and commit with: git commit -eF %s\n", path, path)
+	}
+	fmt.Println(message)
+	return nil
+}
+
+// cleanCommitMessage trims the LLM's answer to the message itself, dropping
+// a surrounding code fence and trailing whitespace on each line
+func cleanCommitMessage(answer string) string {
+	lines := strings.Split(strings.TrimSpace(answer), "\n")
+	if len(lines) > 1 && strings.HasPrefix(lines[0], "```") && strings.TrimSpace(lines[len(lines)-1]) == "```" {
+		lines = lines[1 : len(lines)-1]
+	}
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t\r")
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+func init() {
+	rootCmd.AddCommand(commitMsgCmd)
+
+	commitMsgCmd.Flags().BoolP("all", "a", false, "Describe all changes to tracked files, not just the staged ones")
+	commitMsgCmd.Flags().BoolP("write", "w", false, "Also save the message to .git/COMMIT_EDITMSG")
+	commitMsgCmd.Flags().String("context", "", "Additional context about the change, e.g. the issue it fixes")
+	commitMsgCmd.Flags().Bool("dry-run", false, "Print the prompt and estimated tokens and cost without calling a provider")
+	addRedactFlags(commitMsgCmd)
+	addProviderFlags(commitMsgCmd)
+	addContentFlags(commitMsgCmd)
+}
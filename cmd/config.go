@@ -0,0 +1,211 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/frfahim/gitstory/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// settings holds the merged config files and GITSTORY_* env vars, loaded before every command
+var settings *config.Settings
+
+// loadSettings resolves the config for the current directory and --profile
+func loadSettings(cmd *cobra.Command) error {
+	currentDir, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+	profile, _ := cmd.Flags().GetString("profile")
+	settings, err = config.Load(currentDir, profile)
+	return err
+}
+
+// stringSetting returns a flag's value, or the configured value for key when the flag wasn't given
+func stringSetting(cmd *cobra.Command, flag, key string) string {
+	value, _ := cmd.Flags().GetString(flag)
+	if cmd.Flags().Changed(flag) || settings == nil {
+		return value
+	}
+	if configured, ok := settings.Get(key); ok {
+		return configured
+	}
+	return value
+}
+
+// intSetting is stringSetting for integer flags
+func intSetting(cmd *cobra.Command, flag, key string) int {
+	value, _ := cmd.Flags().GetInt(flag)
+	if cmd.Flags().Changed(flag) || settings == nil {
+		return value
+	}
+	if configured, ok := settings.Get(key); ok {
+		if n, err := strconv.Atoi(configured); err == nil {
+			return n
+		}
+	}
+	return value
+}
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Show and edit GitStory configuration",
+	Long: `Manage persistent defaults for provider, model, platform, base branch,
+commit count, context, excludes, content excludes, redact patterns and output path.
+
+Settings are merged in this order (later wins):
+  1. ~/.config/gitstory/config.yaml     (user defaults, $GITSTORY_CONFIG overrides the path)
+  2. its selected profile
+  3. .gitstory.yaml at the repository root (repo defaults)
+  4. its selected profile
+  5. GITSTORY_<KEY> environment variables (e.g. GITSTORY_PROVIDER)
+  6. command-line flags
+
+The profile is chosen by --profile, then $GITSTORY_PROFILE, then default_profile
+in the repo config, then in the user config.
+
+Example .gitstory.yaml:
+  platform: technical
+  base: develop
+  excludes: [vendor/, "*.pb.go"]
+  content_excludes: [fixtures/]
+  redact: ['ACME-[0-9]{6}']
+  profiles:
+    release:
+      platform: blog
+      provider: claude
+      output: RELEASE_NOTES.md`,
+}
+
+var configListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Show the effective configuration and where each value comes from",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		fmt.Printf("User config: %s\n", settings.UserPath)
+		if settings.RepoPath != "" {
+			fmt.Printf("Repo config: %s\n", settings.RepoPath)
+		}
+		if settings.ProfileName != "" {
+			fmt.Printf("Profile:     %s\n", settings.ProfileName)
+		}
+		fmt.Println()
+
+		for _, key := range config.Keys {
+			value, ok := settings.Get(key)
+			if !ok {
+				fmt.Printf("%-9s (not set)\n", key)
+				continue
+			}
+			fmt.Printf("%-9s = %s    # %s\n", key, value, settings.Sources[key])
+		}
+		return nil
+	},
+}
+
+var configGetCmd = &cobra.Command{
+	Use:       "get <key>",
+	Short:     "Print the effective value of a configuration key",
+	Args:      cobra.ExactArgs(1),
+	ValidArgs: config.Keys,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := (&config.Profile{}).Set(args[0], ""); err != nil {
+			return err
+		}
+		value, ok := settings.Get(args[0])
+		if !ok {
+			return fmt.Errorf("%s is not set", args[0])
+		}
+		fmt.Println(value)
+		return nil
+	},
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set <key> <value>...",
+	Short: "Set a configuration key in the user (or --repo) config file",
+	Long: `Set a configuration key. Writes to the user config unless --repo is given;
+with --profile the key is set inside that profile. An empty value clears the key.
+Use "default_profile" as the key to choose the profile used when --profile is omitted.
+
+List keys (excludes, content_excludes, redact) take one value per pattern, or a
+YAML/JSON list; a single plain value is split on commas (newlines for redact).
+
+Examples:
+  gitstory config set excludes vendor/ '*.pb.go'
+  gitstory config set content_excludes '["*.{snap,golden}", fixtures/]'
+  gitstory config set --repo redact 'ACME-[0-9]{6}'`,
+	Args:      cobra.MinimumNArgs(2),
+	ValidArgs: append([]string{"default_profile"}, config.Keys...),
+	// Only the target file is read, so a malformed config elsewhere can still
+	// be fixed from here, and --profile may name a profile that doesn't exist yet
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		key, values := args[0], args[1:]
+		// Repeated values are the items of a list, taken as they are
+		set := func(p *config.Profile) error {
+			if len(values) > 1 {
+				return p.SetList(key, values)
+			}
+			return p.Set(key, values[0])
+		}
+		repoScope, _ := cmd.Flags().GetBool("repo")
+		profile, _ := cmd.Flags().GetString("profile")
+
+		path, err := config.UserConfigPath()
+		if err != nil {
+			return err
+		}
+		if repoScope {
+			currentDir, err := os.Getwd()
+			if err != nil {
+				return fmt.Errorf("failed to get current directory: %w", err)
+			}
+			if path = config.RepoConfigPath(currentDir); path == "" {
+				return fmt.Errorf("--repo requires running inside a Git repository")
+			}
+		}
+
+		file, err := config.LoadFile(path)
+		if err != nil {
+			return fmt.Errorf("%w (fix it in an editor, then retry)", err)
+		}
+
+		switch {
+		case key == "default_profile":
+			if len(values) > 1 {
+				return fmt.Errorf("%s takes a single value", key)
+			}
+			file.DefaultProfile = values[0]
+		case profile != "":
+			if file.Profiles == nil {
+				file.Profiles = map[string]config.Profile{}
+			}
+			p := file.Profiles[profile]
+			if err := set(&p); err != nil {
+				return err
+			}
+			file.Profiles[profile] = p
+		default:
+			if err := set(&file.Profile); err != nil {
+				return err
+			}
+		}
+
+		if err := file.Save(path); err != nil {
+			return err
+		}
+		fmt.Printf("✅ Set %s in %s\n", key, path)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configListCmd, configGetCmd, configSetCmd)
+	configSetCmd.Flags().Bool("repo", false, "Write to the repository's .gitstory.yaml instead of the user config")
+}
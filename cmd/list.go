@@ -6,15 +6,26 @@ import (
 	"time"
 
 	"github.com/frfahim/gitstory/internal/git"
+	"github.com/frfahim/gitstory/internal/types"
 	"github.com/go-git/go-git/v5/plumbing/object"
 	"github.com/spf13/cobra"
 )
 
 // listCmd represents the list command
 var listCmd = &cobra.Command{
-	Use:   "list",
+	Use:   "list [revision-range] [-- <pathspec>...]",
 	Short: "List recent commits in the current Git repository",
-	Long:  `Show the recent Git commits with hash, author, date, and message.`,
+	Long: `Show the recent Git commits with hash, author, date, and message.
+
+An optional revision range selects commits the way git log does:
+  gitstory list v1.2.0..v1.3.0      # Commits in v1.3.0 but not v1.2.0
+  gitstory list main...feature      # Commits on either side but not both
+  gitstory list origin/main..HEAD   # Commits not yet pushed
+  gitstory list HEAD~20             # Commits reachable from HEAD~20
+
+Pathspecs after "--" (or --path/--exclude) limit commits to a subtree:
+  gitstory list -- services/billing ':(exclude)*.pb.go'`,
+	Args: revisionAndPathArgs,
 	Run: func(cmd *cobra.Command, args []string) {
 		var commits []*object.Commit
 		currentDir, _ := os.Getwd()
@@ -23,53 +34,108 @@ var listCmd = &cobra.Command{
 			fmt.Println("❌ Not a Git repository.")
 			return
 		}
-		num, _ := cmd.Flags().GetInt("number")
+		num := intSetting(cmd, "number", "number")
 		unique, _ := cmd.Flags().GetBool("unique")
-		base, _ := cmd.Flags().GetString("base")
 		if num < 1 {
 			num = 5
 		}
+		opts, err := listOptionsFromFlags(cmd, repo, args, num, "number")
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			return
+		}
 
-		// If unique mode, try to auto-detect base if not explicitly set
-		if unique && (base == "" || base == "auto") {
-			autoBase, err := repo.DetectDefaultBranch()
-			if err != nil {
-				fmt.Printf("⚠️  Could not auto-detect default branch: %v\n", err)
+		rangeSpec := revisionArg(cmd, args)
+		if unique && rangeSpec != "" {
+			fmt.Println("❌ --unique cannot be combined with a revision range")
+			return
+		}
+
+		// In unique mode, auto-detect the base unless one was given
+		var base string
+		if unique {
+			if base, err = baseFromFlags(cmd, repo); err != nil {
+				fmt.Printf("⚠️  %v\n", err)
 				return
 			}
-			base = autoBase
 		}
 
-		if unique {
-			commits, err = repo.ListUniqueCommits(base, num)
+		if rangeSpec != "" {
+			commits, err = repo.ListRangeCommits(rangeSpec, opts)
+			if err != nil {
+				fmt.Printf("❌ Error listing commits in range '%s': %v\n", rangeSpec, err)
+				return
+			}
+			fmt.Printf("🔎 Showing %d commits in range '%s'%s:\n\n", len(commits), rangeSpec, describeListOptions(opts))
+		} else if unique {
+			commits, err = repo.ListUniqueCommitsWithOptions(base, opts)
 			if err != nil {
 				fmt.Printf("❌ Error listing unique commits (base=%s): %v\n", base, err)
 				return
 			}
-			fmt.Printf("🔎 Showing last %d commits unique to branch '%s' (vs base '%s'):\n\n", len(commits), repo.CurrentBranchName(), base)
+			fmt.Printf("🔎 Showing last %d commits unique to branch '%s' (vs base '%s')%s:\n\n", len(commits), repo.CurrentBranchName(), base, describeListOptions(opts))
 		} else {
-			commits, err = repo.ListCommits(num)
+			commits, err = repo.ListCommitsWithOptions(opts)
 			if err != nil {
 				fmt.Printf("❌ Error listing commits: %v\n", err)
 				return
 			}
-			fmt.Printf("🔎 Showing last %d commits on branch '%s':\n\n", len(commits), repo.CurrentBranchName())
+			fmt.Printf("🔎 Showing last %d commits on branch '%s'%s:\n\n", len(commits), repo.CurrentBranchName(), describeListOptions(opts))
+		}
+
+		nameStatus, _ := cmd.Flags().GetBool("name-status")
+		var diffOpts git.DiffOptions
+		if nameStatus {
+			if diffOpts, err = diffOptionsFromFlags(cmd, args); err != nil {
+				fmt.Printf("❌ %v\n", err)
+				return
+			}
 		}
 
 		fmt.Printf("Showing last %d commits:\n\n", len(commits))
 		for _, c := range commits {
-			fmt.Printf("• %s | %s | %s\n  %s\n\n",
+			fmt.Printf("• %s | %s | %s\n  %s\n",
 				c.Hash.String()[:7],
 				c.Author.Name,
 				c.Author.When.Format(time.RFC822),
 				c.Message)
+			if nameStatus {
+				details, err := repo.GetCommitDiffDetailsWithOptions(c, diffOpts)
+				if err != nil {
+					fmt.Printf("  ⚠️  Could not read changed files: %v\n", err)
+				}
+				for _, file := range details.Files {
+					fmt.Printf("  %s\n", nameStatusLine(file))
+				}
+			}
+			fmt.Println()
 		}
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(listCmd)
+	addCommitFilterFlags(listCmd)
 	listCmd.Flags().IntP("number", "n", 5, "Number of commits to show")
 	listCmd.Flags().Bool("unique", false, "Show only commits unique to this branch (compared to main)")
-	listCmd.Flags().String("base", "main", "Base branch name for unique commit comparison (default: auto-detect main/master)")
+	listCmd.Flags().Bool("name-status", false, "Show the files each commit changed, with renames and copies as 'R old -> new'")
+	listCmd.Flags().String("base", "auto", "Base for --unique: a branch, remote branch (origin/main), tag or SHA (default: auto-detect from origin/HEAD, then main/master)")
+}
+
+// nameStatusLine formats a changed file like `git log --name-status`
+func nameStatusLine(file types.FileChange) string {
+	letter := map[string]string{
+		"Insert":            "A",
+		"Delete":            "D",
+		"Modify":            "M",
+		types.StatusRenamed: "R",
+		types.StatusCopied:  "C",
+	}[file.Status]
+	if letter == "" {
+		letter = "?"
+	}
+	if file.OldPath != "" {
+		return fmt.Sprintf("%s  %s -> %s", letter, file.OldPath, file.Path)
+	}
+	return fmt.Sprintf("%s  %s", letter, file.Path)
 }
@@ -0,0 +1,335 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/frfahim/gitstory/internal/conventional"
+	"github.com/frfahim/gitstory/internal/git"
+	"github.com/frfahim/gitstory/internal/llm"
+	"github.com/frfahim/gitstory/internal/semver"
+	"github.com/frfahim/gitstory/internal/types"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/spf13/cobra"
+)
+
+var nextVersionCmd = &cobra.Command{
+	Use:   "next-version",
+	Short: "Suggest the next semantic version from the commits since the last release",
+	Long: `Find the latest semantic version tag reachable from HEAD and recommend the next
+version from the commits since the last release: major for breaking changes,
+minor for features and patch for fixes (Conventional Commits). With --ai an LLM
+also reviews the diffs, which catches breaking changes the messages don't mention;
+the larger of the two bumps wins.
+
+While the major version is zero, breaking changes bump the minor version.
+
+Examples:
+  gitstory next-version                 # Recommended version and why
+  gitstory next-version --pre rc        # Next release candidate: v1.3.0-rc.1, then -rc.2, ...
+  gitstory next-version --short         # Just the version, e.g. for: git tag $(gitstory next-version --short)
+  gitstory next-version --json --ai     # Machine readable, with the LLM's judgment`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runNextVersion(cmd)
+	},
+}
+
+// nextVersionReport is the --json output of next-version
+type nextVersionReport struct {
+	// Current is the latest version tag, empty when there is none
+	Current string `json:"current,omitempty"`
+	// Next is the recommended tag, empty when no release is needed
+	Next    string   `json:"next,omitempty"`
+	Bump    string   `json:"bump"`
+	Commits int      `json:"commits"`
+	Reasons []string `json:"reasons"`
+}
+
+// taggedVersion is a version tag
+type taggedVersion struct {
+	tag     git.Tag
+	version semver.Version
+}
+
+func runNextVersion(cmd *cobra.Command) error {
+	channel, _ := cmd.Flags().GetString("pre")
+	useAI, _ := cmd.Flags().GetBool("ai")
+	short, _ := cmd.Flags().GetBool("short")
+	asJSON, _ := cmd.Flags().GetBool("json")
+
+	// Progress goes to stderr when stdout is for scripts
+	if short || asJSON {
+		cmd.SetOut(os.Stderr)
+	}
+	out := cmd.OutOrStdout()
+
+	if channel != "" {
+		if _, err := semver.Parse("0.0.0-" + channel + ".1"); err != nil {
+			return fmt.Errorf("invalid --pre channel %q", channel)
+		}
+	}
+
+	currentDir, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+	repo, err := git.OpenRepository(currentDir)
+	if err != nil {
+		return fmt.Errorf("❌ Not a Git repository: %w", err)
+	}
+
+	stable, latest, err := latestVersions(repo)
+	if err != nil {
+		return err
+	}
+
+	// The bump covers everything since the last release, pre-releases included
+	sinceStable, err := commitsSince(repo, stable)
+	if err != nil {
+		return err
+	}
+	sinceLatest := sinceStable
+	if latest != stable {
+		if sinceLatest, err = commitsSince(repo, latest); err != nil {
+			return err
+		}
+	}
+
+	data := make([]types.CommitData, len(sinceStable))
+	for i, commit := range sinceStable {
+		data[i] = git.NewCommitData(commit)
+	}
+	bump := conventional.RecommendBump(data)
+	level := bump.Level
+	report := nextVersionReport{Commits: len(sinceStable), Reasons: bumpReasons(bump)}
+
+	var stableVersion, latestVersion semver.Version
+	prefix := "v"
+	if latest != nil {
+		latestVersion = latest.version
+		report.Current = latest.tag.Name
+		prefix = strings.TrimSuffix(latest.tag.Name, latest.version.String())
+	}
+	if stable != nil {
+		stableVersion = stable.version
+		fmt.Fprintf(out, "🏷️ Latest release: %s", stable.tag.Name)
+		if latest != stable {
+			fmt.Fprintf(out, " (pre-release %s)", latest.tag.Name)
+		}
+		fmt.Fprintf(out, ", %d commit(s) since\n", len(sinceStable))
+	} else if latest != nil {
+		fmt.Fprintf(out, "🏷️ No release yet (pre-release %s), %d commit(s)\n", latest.tag.Name, len(sinceStable))
+	} else {
+		fmt.Fprintf(out, "🏷️ No version tags yet, %d commit(s)\n", len(sinceStable))
+	}
+
+	if useAI && len(sinceLatest) > 0 {
+		judged, reasons, err := judgeBump(cmd, repo, sinceStable, stableVersion)
+		if err != nil {
+			return err
+		}
+		report.Reasons = append(report.Reasons, reasons...)
+		if judged > level {
+			level = judged
+		}
+	}
+	if effective := stableVersion.Effective(level); effective != level {
+		report.Reasons = append(report.Reasons, fmt.Sprintf("major version zero: a %s change bumps the %s version", level, effective))
+		level = effective
+	}
+	report.Bump = level.String()
+
+	// Without new commits there is nothing to release, except promoting a
+	// pre-release; the same goes for commits that don't change the version
+	promoting := channel == "" && latestVersion.IsPrerelease()
+	if (len(sinceLatest) == 0 || level == semver.None) && !promoting {
+		switch {
+		case asJSON:
+			return writeJSON(report)
+		case len(sinceLatest) == 0:
+			fmt.Fprintf(out, "ℹ️ No release needed: no commits since %s\n", report.Current)
+		default:
+			fmt.Fprintln(out, "ℹ️ No release needed: no features, fixes or breaking changes")
+		}
+		return nil
+	}
+
+	next := semver.Next(stableVersion, latestVersion, level, channel)
+	report.Next = prefix + next.String()
+	switch {
+	case asJSON:
+		return writeJSON(report)
+	case short:
+		fmt.Println(report.Next)
+	default:
+		fmt.Fprintf(out, "📈 Bump: %s\n", report.Bump)
+		for _, reason := range report.Reasons {
+			fmt.Fprintf(out, "   • %s\n", reason)
+		}
+		fmt.Fprintf(out, "➡️ Next version: %s\n", report.Next)
+	}
+	return nil
+}
+
+// latestVersions returns the highest release and the highest version,
+// pre-releases included, among the semver tags reachable from HEAD; either
+// is nil when there is none. Tags that aren't semantic versions are ignored.
+func latestVersions(repo *git.Repository) (stable, latest *taggedVersion, err error) {
+	tags, err := repo.TagsReachableFrom("HEAD")
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, tag := range tags {
+		version, err := semver.Parse(tag.Name)
+		if err != nil {
+			continue
+		}
+		candidate := &taggedVersion{tag: tag, version: version}
+		if latest == nil || semver.Compare(version, latest.version) > 0 {
+			latest = candidate
+		}
+		if !version.IsPrerelease() && (stable == nil || semver.Compare(version, stable.version) > 0) {
+			stable = candidate
+		}
+	}
+	if stable != nil && semver.Compare(stable.version, latest.version) == 0 {
+		latest = stable
+	}
+	return stable, latest, nil
+}
+
+// commitsSince returns the commits after a version tag, or all of them when it's nil
+func commitsSince(repo *git.Repository, since *taggedVersion) ([]*object.Commit, error) {
+	spec := "HEAD"
+	if since != nil {
+		spec = since.tag.Name + "..HEAD"
+	}
+	commits, err := repo.ListRangeCommits(spec, git.ListOptions{SkipMerges: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list commits in %s: %w", spec, err)
+	}
+	return commits, nil
+}
+
+// bumpReasons lists the commits behind a bump, most significant first
+func bumpReasons(bump conventional.Bump) []string {
+	var reasons []string
+	for _, commit := range bump.Breaking {
+		note := commit.Conventional.BreakingNote
+		if note == "" {
+			note = commit.Conventional.Subject
+		}
+		reasons = append(reasons, fmt.Sprintf("breaking change %s: %s", commit.Hash, note))
+	}
+	for _, commit := range bump.Features {
+		reasons = append(reasons, fmt.Sprintf("feature %s: %s", commit.Hash, commit.Conventional.Subject))
+	}
+	for _, commit := range bump.Fixes {
+		reasons = append(reasons, fmt.Sprintf("%s %s: %s", commit.Conventional.Type, commit.Hash, commit.Conventional.Subject))
+	}
+	return reasons
+}
+
+// judgeBump has the LLM judge the bump from the commits' diffs and returns
+// its verdict and reasoning
+func judgeBump(cmd *cobra.Command, repo *git.Repository, commits []*object.Commit, current semver.Version) (semver.Level, []string, error) {
+	out := cmd.OutOrStdout()
+	providers, err := providersFromFlags(cmd, false)
+	if err != nil {
+		return semver.None, nil, err
+	}
+	client, err := newClientFromFlags(cmd, providers)
+	if err != nil {
+		return semver.None, nil, err
+	}
+
+	allContent, _ := cmd.Flags().GetBool("all-content")
+	noCache, _ := cmd.Flags().GetBool("no-cache")
+	data, err := repo.ListCommitSummarize(commits, git.DiffOptions{
+		IncludeDiff:     true,
+		Merges:          git.MergeSkip,
+		ContentExcludes: contentExcludesFromFlags(cmd),
+		AllContent:      allContent,
+		NoCache:         noCache,
+	})
+	if err != nil {
+		return semver.None, nil, fmt.Errorf("failed to get commit diffs: %w", err)
+	}
+
+	if err := redactFromFlags(cmd, data); err != nil {
+		return semver.None, nil, err
+	}
+
+	userContext := fmt.Sprintf("The current version is %s.", current)
+	if extra := stringSetting(cmd, "context", "context"); extra != "" {
+		userContext += " " + extra
+	}
+
+	fmt.Fprintf(out, "🧠 Judging the version bump with %s...\n", joinProviders(client.Providers(), " → "))
+	response, err := client.Summarize(context.Background(), &llm.SummaryRequest{
+		Commits:     data,
+		Platform:    llm.VersionBump,
+		UserContext: userContext,
+	})
+	if err != nil {
+		return semver.None, nil, summarizeError(err)
+	}
+	level, reasoning, err := parseBumpJudgment(response.Summary)
+	if err != nil {
+		return semver.None, nil, fmt.Errorf("%s: %w", response.Provider, err)
+	}
+
+	reasons := []string{fmt.Sprintf("%s judged %s", response.Provider, level)}
+	for _, line := range strings.Split(reasoning, "\n") {
+		if line = strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(line), "-*•")); line != "" {
+			reasons = append(reasons, line)
+		}
+	}
+	return level, reasons, nil
+}
+
+// parseBumpJudgment reads the bump from the first line of the LLM's answer,
+// tolerating markdown such as "**Minor**", and returns the rest as reasoning
+func parseBumpJudgment(answer string) (semver.Level, string, error) {
+	answer = strings.TrimSpace(answer)
+	first, rest, _ := strings.Cut(answer, "\n")
+	word := strings.Trim(strings.ToLower(first), " \t*#`_.:")
+	word = strings.TrimSpace(strings.TrimPrefix(word, "bump"))
+	word = strings.Trim(word, " \t*`_.:")
+	if fields := strings.Fields(word); len(fields) > 0 {
+		word = fields[0]
+	}
+	level, err := semver.ParseLevel(word)
+	if err != nil {
+		return semver.None, "", fmt.Errorf("unexpected answer %q: %w", first, err)
+	}
+	return level, strings.TrimSpace(rest), nil
+}
+
+// writeJSON prints v as indented JSON
+func writeJSON(v any) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+func init() {
+	rootCmd.AddCommand(nextVersionCmd)
+
+	nextVersionCmd.Flags().String("pre", "", "Pre-release channel, e.g. rc or beta: v1.3.0-rc.1, then -rc.2, ...")
+	nextVersionCmd.Flags().Bool("short", false, "Print only the next version, nothing when no release is needed")
+	nextVersionCmd.Flags().Bool("json", false, "Print the current and next version, bump and reasons as JSON")
+
+	// AI options
+	nextVersionCmd.Flags().Bool("ai", false, "Also have the LLM judge the bump from the commits' diffs")
+	nextVersionCmd.Flags().String("context", "", "Additional context about the project for --ai")
+	addProviderFlags(nextVersionCmd)
+	addContentFlags(nextVersionCmd)
+	addRedactFlags(nextVersionCmd)
+	addCacheFlag(nextVersionCmd)
+}
@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/frfahim/gitstory/internal/llm"
+	"github.com/spf13/cobra"
+)
+
+// addProviderFlags registers the provider and generation flags shared by the commands that call an LLM
+func addProviderFlags(cmd *cobra.Command) {
+	cmd.Flags().String("provider", "", "LLM provider, or a comma separated fallback chain (openai, gemini, claude, ollama, openai-compatible)")
+	cmd.Flags().String("model", "", "Model name (default: $<PROVIDER>_MODEL or the provider's default)")
+	cmd.Flags().Float64("temperature", 0.7, "Sampling temperature (0-2, Claude 0-1)")
+	cmd.Flags().Int("max-tokens", 0, "Maximum output tokens (default: per-platform limit)")
+	cmd.Flags().Duration("timeout", 2*time.Minute, "Timeout for each request to the provider (0 for none)")
+	cmd.Flags().Int("context-window", 0, "Model context size in tokens (default: known size for the model; Ollama 4096)")
+	cmd.Flags().Int("max-retries", llm.DefaultRetryPolicy.MaxRetries, "Retries on rate limits, network errors and provider outages")
+
+	cmd.RegisterFlagCompletionFunc("provider", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"openai", "gemini", "claude", "ollama", "openai-compatible"}, cobra.ShellCompDirectiveNoFileComp
+	})
+}
+
+// providersFromFlags returns the --provider fallback chain, or every
+// configured provider when none was given. Like the other helpers here, it
+// reports progress to the command's output. A dry run needs no credentials,
+// so it falls back to showing what OpenAI would receive.
+func providersFromFlags(cmd *cobra.Command, dryRun bool) ([]llm.Provider, error) {
+	provider := stringSetting(cmd, "provider", "provider")
+	if provider != "" {
+		providers, err := parseProviderChain(provider)
+		if err != nil {
+			return nil, fmt.Errorf("invalid provider: %w", err)
+		}
+		return providers, nil
+	}
+
+	providers := llm.DetectAvailableProviders()
+	if len(providers) == 0 && dryRun {
+		// Nothing is sent, so show what the default provider would receive
+		providers = []llm.Provider{llm.OpenAI}
+	}
+	if len(providers) == 0 {
+		return nil, fmt.Errorf("❌ No LLM providers configured. Please set OPENAI_API_KEY, GEMINI_API_KEY, CLAUDE_API_KEY or OLLAMA_HOST")
+	}
+	if len(providers) == 1 {
+		fmt.Fprintf(cmd.OutOrStdout(), "🤖 Using %s (auto-detected)\n", providers[0])
+	} else {
+		fmt.Fprintf(cmd.OutOrStdout(), "🤖 Using %s, falling back to %s (auto-detected)\n", providers[0], joinProviders(providers[1:], ", "))
+	}
+	return providers, nil
+}
+
+// clientConfigFromFlags returns the generation settings for the i-th provider
+// of the chain; --model applies to the first provider, the rest use their defaults
+func clientConfigFromFlags(cmd *cobra.Command, i int, p llm.Provider) llm.ClientConfig {
+	maxTokens, _ := cmd.Flags().GetInt("max-tokens")
+	contextWindow, _ := cmd.Flags().GetInt("context-window")
+	clientConfig := llm.ClientConfig{
+		Provider:      p,
+		MaxTokens:     maxTokens,
+		ContextWindow: contextWindow,
+	}
+	if i == 0 {
+		clientConfig.Model = stringSetting(cmd, "model", "model")
+	}
+	if cmd.Flags().Changed("temperature") {
+		temperature, _ := cmd.Flags().GetFloat64("temperature")
+		clientConfig.Temperature = &temperature
+	}
+	return clientConfig
+}
+
+// newClientFromFlags creates the client chain for providers: each provider
+// retries on its own and batches large requests to fit its context window,
+// and the chain falls back to the next provider when one fails
+func newClientFromFlags(cmd *cobra.Command, providers []llm.Provider) (*llm.FallbackClient, error) {
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+	maxRetries, _ := cmd.Flags().GetInt("max-retries")
+	if maxRetries < 0 {
+		return nil, fmt.Errorf("--max-retries must not be negative, got %d", maxRetries)
+	}
+	retryPolicy := llm.DefaultRetryPolicy
+	retryPolicy.MaxRetries = maxRetries
+	retryPolicy.Timeout = timeout
+	retryPolicy.OnRetry = func(attempt int, delay time.Duration, err *llm.Error) {
+		fmt.Fprintf(cmd.OutOrStdout(), "⏳ %s (%s), retrying in %s (%d/%d)...\n", err.Provider, err.Kind, delay.Round(100*time.Millisecond), attempt, maxRetries)
+	}
+
+	var clients []llm.Client
+	for i, p := range providers {
+		fmt.Fprintf(cmd.OutOrStdout(), "🔧 Creating %s client...\n", p)
+		clientConfig := clientConfigFromFlags(cmd, i, p)
+		client, err := llm.NewClient(clientConfig)
+		if err != nil {
+			if len(providers) == 1 {
+				return nil, fmt.Errorf("failed to create LLM client: %w", err)
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "⚠️ Skipping %s: %v\n", p, err)
+			continue
+		}
+		// Large commit sets are summarized in batches sized to this provider's context window
+		budgeted := llm.NewMapReduceClient(llm.NewRetryClient(client, retryPolicy), clientConfig)
+		budgeted.OnBatch = func(batch, batches int) {
+			fmt.Fprintf(cmd.OutOrStdout(), "📦 Summarizing batch %d/%d with %s...\n", batch, batches, p)
+		}
+		clients = append(clients, budgeted)
+	}
+	if len(clients) == 0 {
+		return nil, fmt.Errorf("failed to create LLM client: none of %s could be used", joinProviders(providers, ", "))
+	}
+
+	client, err := llm.NewFallbackClient(clients...)
+	if err != nil {
+		return nil, err
+	}
+	client.OnFallback = func(failed llm.Provider, err error, next llm.Provider) {
+		fmt.Fprintf(cmd.OutOrStdout(), "⚠️ %s failed (%v), falling back to %s...\n", failed, err, next)
+	}
+	return client, nil
+}
@@ -11,6 +11,13 @@ var rootCmd = &cobra.Command{
 	Short: "Turn your commits into stories worth sharing",
 	Long: `GitStory analyzes your Git commits and generates intelligent summarize 
 that you can share on social media, blogs, or documentation.`,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		return loadSettings(cmd)
+	},
+}
+
+func init() {
+	rootCmd.PersistentFlags().String("profile", "", "Named config profile to use (see 'gitstory config')")
 }
 
 func Execute() {
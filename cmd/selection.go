@@ -0,0 +1,235 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/frfahim/gitstory/internal/git"
+	"github.com/frfahim/gitstory/internal/redact"
+	"github.com/frfahim/gitstory/internal/types"
+	"github.com/spf13/cobra"
+)
+
+// addCommitFilterFlags registers the commit selection filters shared by list, analyze and summarize
+func addCommitFilterFlags(cmd *cobra.Command) {
+	cmd.Flags().String("since", "", `Only commits after a date (RFC3339, YYYY-MM-DD, "yesterday", "monday", "2 weeks ago")`)
+	cmd.Flags().String("until", "", "Only commits up to a date, including all of a day given as YYYY-MM-DD or \"yesterday\" (same formats as --since)")
+	cmd.Flags().Bool("author-date", false, "Filter --since/--until on author date instead of committer date")
+	cmd.Flags().StringArray("author", nil, "Only commits whose author name/email matches (regex or substring, repeatable)")
+	cmd.Flags().StringArray("committer", nil, "Only commits whose committer name/email matches (regex or substring, repeatable)")
+	cmd.Flags().Bool("mine", false, "Only commits authored by you (user.email from git config)")
+	cmd.Flags().String("merges", string(git.MergeFirstParent), "How to treat merge commits (first-parent, combined, skip)")
+	cmd.Flags().StringArray("path", nil, "Only commits and files matching a pathspec (glob or directory, repeatable)")
+	cmd.Flags().StringArray("exclude", nil, "Leave out commits and files matching a pathspec (repeatable)")
+	cmd.RegisterFlagCompletionFunc("merges", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{string(git.MergeFirstParent), string(git.MergeCombined), string(git.MergeSkip)}, cobra.ShellCompDirectiveNoFileComp
+	})
+}
+
+// revisionAndPathArgs accepts an optional revision range, optionally followed
+// by "-- <pathspec>..." as in `gitstory list v1.2.0.. -- services/billing`
+func revisionAndPathArgs(cmd *cobra.Command, args []string) error {
+	if dash := cmd.ArgsLenAtDash(); dash >= 0 {
+		args = args[:dash]
+	}
+	return cobra.MaximumNArgs(1)(cmd, args)
+}
+
+// revisionArg returns the revision range argument, or "" when none was given
+func revisionArg(cmd *cobra.Command, args []string) string {
+	if dash := cmd.ArgsLenAtDash(); dash >= 0 {
+		args = args[:dash]
+	}
+	if len(args) == 0 {
+		return ""
+	}
+	return args[0]
+}
+
+// pathspecFromFlags merges --path, --exclude, the configured excludes and the
+// arguments after "--" into pathspec entries
+func pathspecFromFlags(cmd *cobra.Command, args []string) []string {
+	paths, _ := cmd.Flags().GetStringArray("path")
+	excludes, _ := cmd.Flags().GetStringArray("exclude")
+
+	var spec []string
+	spec = append(spec, paths...)
+	if dash := cmd.ArgsLenAtDash(); dash >= 0 {
+		spec = append(spec, args[dash:]...)
+	}
+	if settings != nil {
+		excludes = append(excludes, settings.Excludes...)
+	}
+	for _, exclude := range excludes {
+		spec = append(spec, ":(exclude)"+exclude)
+	}
+	return spec
+}
+
+// listOptionsFromFlags builds git.ListOptions from the shared filter flags.
+// When a date range or revision range is given and countFlag wasn't set
+// explicitly, the count limit is dropped so "--since monday" returns
+// everything since Monday and "v1.2.0..v1.3.0" the whole release.
+func listOptionsFromFlags(cmd *cobra.Command, repo *git.Repository, args []string, limit int, countFlag string) (git.ListOptions, error) {
+	since, _ := cmd.Flags().GetString("since")
+	until, _ := cmd.Flags().GetString("until")
+	authorDate, _ := cmd.Flags().GetBool("author-date")
+	authors, _ := cmd.Flags().GetStringArray("author")
+	committers, _ := cmd.Flags().GetStringArray("committer")
+	mine, _ := cmd.Flags().GetBool("mine")
+
+	opts := git.ListOptions{
+		Limit:         limit,
+		UseAuthorDate: authorDate,
+		Authors:       authors,
+		Committers:    committers,
+		Paths:         pathspecFromFlags(cmd, args),
+	}
+	now := time.Now()
+
+	if mine {
+		email, err := repo.UserEmail()
+		if err != nil {
+			return opts, fmt.Errorf("--mine: %w", err)
+		}
+		opts.Authors = append(opts.Authors, git.EmailPattern(email))
+	}
+
+	merges, err := mergeStrategyFromFlags(cmd)
+	if err != nil {
+		return opts, err
+	}
+	opts.SkipMerges = merges == git.MergeSkip
+
+	if since != "" {
+		if opts.Since, err = git.ParseDate(since, now); err != nil {
+			return opts, fmt.Errorf("invalid --since: %w", err)
+		}
+	}
+	if until != "" {
+		if opts.Until, err = git.ParseUntil(until, now); err != nil {
+			return opts, fmt.Errorf("invalid --until: %w", err)
+		}
+	}
+	if !opts.Since.IsZero() && !opts.Until.IsZero() && opts.Since.After(opts.Until) {
+		return opts, fmt.Errorf("--since (%s) is after --until (%s)", since, until)
+	}
+
+	if (opts.HasDateRange() || revisionArg(cmd, args) != "") && !cmd.Flags().Changed(countFlag) {
+		opts.Limit = 0
+	}
+	return opts, nil
+}
+
+// diffOptionsFromFlags builds git.DiffOptions from the shared filter flags
+func diffOptionsFromFlags(cmd *cobra.Command, args []string) (git.DiffOptions, error) {
+	merges, err := mergeStrategyFromFlags(cmd)
+	if err != nil {
+		return git.DiffOptions{}, err
+	}
+	allContent, _ := cmd.Flags().GetBool("all-content")
+	noCache, _ := cmd.Flags().GetBool("no-cache")
+	return git.DiffOptions{
+		IncludeDiff:     true,
+		Merges:          merges,
+		Paths:           pathspecFromFlags(cmd, args),
+		ContentExcludes: contentExcludesFromFlags(cmd),
+		AllContent:      allContent,
+		NoCache:         noCache,
+	}, nil
+}
+
+// addCacheFlag registers --no-cache for commands that extract commit diffs
+func addCacheFlag(cmd *cobra.Command) {
+	cmd.Flags().Bool("no-cache", false, "Recompute commit diffs instead of reusing .git/gitstory/cache")
+}
+
+// addContentFlags registers the flags deciding which file contents reach the prompt
+func addContentFlags(cmd *cobra.Command) {
+	cmd.Flags().StringArray("exclude-content", nil, `Send only the stats, not the changes, of files matching a pathspec (repeatable; ":!pattern" keeps a default-excluded file)`)
+	cmd.Flags().Bool("all-content", false, "Send the changes of lockfiles, vendored, generated and minified files too")
+}
+
+// contentExcludesFromFlags merges --exclude-content and the configured content excludes
+func contentExcludesFromFlags(cmd *cobra.Command) []string {
+	excludes, _ := cmd.Flags().GetStringArray("exclude-content")
+	if settings != nil {
+		excludes = append(append([]string{}, settings.ContentExcludes...), excludes...)
+	}
+	return excludes
+}
+
+// addRedactFlags registers the flags deciding which secrets are redacted
+// before repository content is sent to a provider
+func addRedactFlags(cmd *cobra.Command) {
+	cmd.Flags().StringArray("redact", nil, "Extra regex for secrets to redact from the prompt (repeatable; a group named 'secret' limits the replacement)")
+	cmd.Flags().Bool("no-redact", false, "Send code changes without redacting secrets")
+}
+
+// redactFromFlags strips secrets from commits with the built-in, configured
+// and --redact patterns, unless --no-redact was given, and reports what it replaced
+func redactFromFlags(cmd *cobra.Command, commits []types.CommitData) error {
+	if noRedact, _ := cmd.Flags().GetBool("no-redact"); noRedact {
+		return nil
+	}
+	patterns, _ := cmd.Flags().GetStringArray("redact")
+	if settings != nil {
+		patterns = append(patterns, settings.Redact...)
+	}
+	redactor, err := redact.New(patterns)
+	if err != nil {
+		return err
+	}
+	if report := redactor.Commits(commits); report.Total() > 0 {
+		fmt.Fprintf(cmd.OutOrStdout(), "🔒 Redacted %d secret(s) (%s)\n", report.Total(), report)
+	}
+	return nil
+}
+
+// baseFromFlags returns the --base setting for --unique, detecting the
+// repository's default branch when it is "auto" or empty
+func baseFromFlags(cmd *cobra.Command, repo *git.Repository) (string, error) {
+	base := stringSetting(cmd, "base", "base")
+	if base != "" && base != "auto" {
+		return base, nil
+	}
+	detected, err := repo.DetectDefaultBranch()
+	if err != nil {
+		return "", fmt.Errorf("could not auto-detect default branch, pass --base: %w", err)
+	}
+	return detected, nil
+}
+
+func mergeStrategyFromFlags(cmd *cobra.Command) (git.MergeStrategy, error) {
+	merges, _ := cmd.Flags().GetString("merges")
+	strategy, err := git.ParseMergeStrategy(merges)
+	if err != nil {
+		return "", fmt.Errorf("invalid --merges: %w", err)
+	}
+	return strategy, nil
+}
+
+// describeListOptions returns a short human description of the active filters, e.g. " since 2024-05-06"
+func describeListOptions(opts git.ListOptions) string {
+	var parts []string
+	if !opts.Since.IsZero() {
+		parts = append(parts, "since "+opts.Since.Format("2006-01-02 15:04"))
+	}
+	if !opts.Until.IsZero() {
+		parts = append(parts, "until "+opts.Until.Format("2006-01-02 15:04"))
+	}
+	if len(opts.Authors) > 0 {
+		parts = append(parts, "by "+strings.Join(opts.Authors, ", "))
+	}
+	if len(opts.Committers) > 0 {
+		parts = append(parts, "committed by "+strings.Join(opts.Committers, ", "))
+	}
+	if len(opts.Paths) > 0 {
+		parts = append(parts, "in "+strings.Join(opts.Paths, " "))
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return " " + strings.Join(parts, " ")
+}
@@ -2,6 +2,8 @@ package cmd
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"strconv"
@@ -16,16 +18,20 @@ import (
 )
 
 var summarizeCmd = &cobra.Command{
-	Use:   "summarize",
+	Use:   "summarize [revision-range] [-- <pathspec>...]",
 	Short: "Generate AI-powered summarize of your commits details",
-	Long: `Generate intelligent summarize of your git commits details using AI providers like OpenAI and Gemini.
+	Long: `Generate intelligent summarize of your git commits details using AI providers like OpenAI, Gemini and Claude.
 Supports different platforms (twitter/X, blog, linkedin, technical, notes) with optimized prompts.
 
 Examples:
   gitstory summarize                                    # Interactive mode (coming soon)
   gitstory summarize --platform blog                    # Auto-detect provider
   gitstory summarize --provider gemini --platform twitter/X
-  gitstory summarize --platform technical --commits 10 --context "Sprint 23"`,
+  gitstory summarize --platform technical --commits 10 --context "Sprint 23"
+  gitstory summarize --platform notes --since monday     # Everything since Monday
+  gitstory summarize v1.2.0..v1.3.0 --platform blog      # Release notes between two tags
+  gitstory summarize --since monday -- services/billing/ # Only the billing service`,
+	Args: revisionAndPathArgs,
 
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return runSummarize(cmd, args)
@@ -34,13 +40,15 @@ Examples:
 
 func runSummarize(cmd *cobra.Command, args []string) error {
 	// Get flags
-	provider, _ := cmd.Flags().GetString("provider")
-	platform, _ := cmd.Flags().GetString("platform")
-	userContext, _ := cmd.Flags().GetString("context")
-	numbers, _ := cmd.Flags().GetString("numbers")
+	platform := stringSetting(cmd, "platform", "platform")
+	userContext := stringSetting(cmd, "context", "context")
+	numbers := stringSetting(cmd, "numbers", "number")
 	unique, _ := cmd.Flags().GetBool("unique")
-	base, _ := cmd.Flags().GetString("base")
-	output, _ := cmd.Flags().GetString("output")
+	output := stringSetting(cmd, "output", "output")
+	stream, _ := cmd.Flags().GetBool("stream")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	// A dry run only writes its JSON report when --output is given explicitly, never to a configured output
+	dryRunOutput, _ := cmd.Flags().GetString("output")
 
 	// Validate platform
 	if platform == "" {
@@ -72,14 +80,32 @@ func runSummarize(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	opts, err := listOptionsFromFlags(cmd, repo, args, numCommits, "numbers")
+	if err != nil {
+		return err
+	}
+
 	// Get commits based on options
 	var commits []*object.Commit
-	if unique {
-		fmt.Printf("🔍 Getting unique commits from current branch compared to %s...\n", base)
-		commits, err = repo.ListUniqueCommits(base, numCommits)
+	if rangeSpec := revisionArg(cmd, args); rangeSpec != "" {
+		if unique {
+			return fmt.Errorf("--unique cannot be combined with a revision range")
+		}
+		fmt.Printf("🔍 Getting commits in range %s%s...\n", rangeSpec, describeListOptions(opts))
+		commits, err = repo.ListRangeCommits(rangeSpec, opts)
+	} else if unique {
+		var base string
+		if base, err = baseFromFlags(cmd, repo); err != nil {
+			return err
+		}
+		fmt.Printf("🔍 Getting unique commits from current branch compared to %s%s...\n", base, describeListOptions(opts))
+		commits, err = repo.ListUniqueCommitsWithOptions(base, opts)
+	} else if opts.Limit == 0 {
+		fmt.Printf("🔍 Getting commits%s...\n", describeListOptions(opts))
+		commits, err = repo.ListCommitsWithOptions(opts)
 	} else {
-		fmt.Printf("🔍 Getting last %d commits...\n", numCommits)
-		commits, err = repo.ListCommits(numCommits)
+		fmt.Printf("🔍 Getting last %d commits%s...\n", numCommits, describeListOptions(opts))
+		commits, err = repo.ListCommitsWithOptions(opts)
 	}
 
 	if err != nil {
@@ -92,68 +118,87 @@ func runSummarize(cmd *cobra.Command, args []string) error {
 	}
 
 	// Convert to commit summarize then to LLM format
-	summarizeCommitList, err := repo.ListCommitSummarize(commits)
+	diffOpts, err := diffOptionsFromFlags(cmd, args)
+	if err != nil {
+		return err
+	}
+	summarizeCommitList, err := repo.ListCommitSummarize(commits, diffOpts)
 	if err != nil {
 		return fmt.Errorf("failed to get commit summarizes: %w", err)
 	}
 
 	fmt.Printf("📝 Found %d commit(s) to summarize\n", len(summarizeCommitList))
 
-	// Smart provider selection
-	if provider == "" {
-		available := llm.DetectAvailableProviders()
-		if len(available) == 0 {
-			return fmt.Errorf("❌ No LLM providers configured. Please set OPENAI_API_KEY or GEMINI_API_KEY")
-		}
-		if len(available) == 1 {
-			provider = string(available[0])
-			fmt.Printf("🤖 Using %s (auto-detected)\n", provider)
-		} else {
-			fmt.Printf("Multiple providers available: %v\n", available)
-			fmt.Println("Use --provider to specify or run with --interactive (coming soon)")
-			provider = string(available[0]) // Use first available
-			fmt.Printf("🤖 Using %s (first available)\n", provider)
-		}
+	// Strip secrets before anything leaves the machine
+	if err := redactFromFlags(cmd, summarizeCommitList); err != nil {
+		return err
 	}
 
-	// Validate provider
-	if err := llm.ValidateProvider(provider); err != nil {
-		return fmt.Errorf("invalid provider: %w", err)
+	// Create summary request
+	request := &llm.SummaryRequest{
+		Commits:     summarizeCommitList,
+		Platform:    normalizedPlatform,
+		UserContext: userContext,
 	}
 
-	// Create LLM client
-	fmt.Printf("🔧 Creating %s client...\n", provider)
-	client, err := llm.NewClient(llm.ClientConfig{
-		Provider: llm.Provider(provider),
-	})
+	// Provider selection: an explicit comma separated chain, or every configured provider
+	providers, err := providersFromFlags(cmd, dryRun)
 	if err != nil {
-		return fmt.Errorf("failed to create LLM client: %w", err)
+		return err
 	}
 
+	if dryRun {
+		var rendered []*llm.RenderedPrompt
+		for i, p := range providers {
+			rendered = append(rendered, llm.RenderPrompt(clientConfigFromFlags(cmd, i, p), request))
+		}
+		return displayDryRun(rendered, dryRunOutput)
+	}
+
+	client, err := newClientFromFlags(cmd, providers)
+	if err != nil {
+		return err
+	}
+	providerNames := joinProviders(client.Providers(), " → ")
+
 	// Validate credentials (skip for now since it's commented out in interface)
-	fmt.Printf("🔑 Using %s provider...\n", provider)
+	fmt.Printf("🔑 Using %s provider...\n", providerNames)
 	// TODO: Add credential validation when interface is updated
 	// if err := client.ValidateCredentials(ctx); err != nil {
 	//     return fmt.Errorf("credential validation failed: %w", err)
 	// }
 
-	// Create summary request
-	request := &llm.SummaryRequest{
-		Commits:     summarizeCommitList,
-		Platform:    normalizedPlatform,
-		UserContext: userContext,
-	}
-
 	// Generate summary
 	ctx := context.Background()
-	fmt.Printf("🧠 Generating %s summary using %s...\n", normalizedPlatform, provider)
-	response, err := client.Summarize(ctx, request)
-	if err != nil {
-		return fmt.Errorf("failed to generate summary: %w", err)
+	fmt.Printf("🧠 Generating %s summary using %s...\n", normalizedPlatform, providerNames)
+	var response *llm.SummaryResponse
+	if stream {
+		// Print the header with the first chunk, then each chunk as it arrives
+		headerShown := false
+		response, err = llm.SummarizeStream(ctx, client, request, func(delta string) {
+			if !headerShown {
+				displaySummaryHeader(normalizedPlatform)
+				headerShown = true
+			}
+			fmt.Print(delta)
+		})
+		if headerShown {
+			fmt.Println()
+		}
+		if err != nil {
+			return summarizeError(err)
+		}
+	} else {
+		response, err = client.Summarize(ctx, request)
+		if err != nil {
+			return summarizeError(err)
+		}
+		displaySummaryHeader(normalizedPlatform)
+		fmt.Println(response.Summary)
 	}
 
-	// Display result
-	displaySummary(response, normalizedPlatform)
+	// Display stats
+	displaySummaryFooter(response, normalizedPlatform)
 
 	// Save to file if requested
 	if output != "" {
@@ -167,7 +212,91 @@ func runSummarize(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-func displaySummary(response *llm.SummaryResponse, platform llm.Platform) {
+// displayDryRun prints the prompts each provider would receive, or writes them as JSON to output
+func displayDryRun(rendered []*llm.RenderedPrompt, output string) error {
+	if output != "" {
+		data, err := json.MarshalIndent(rendered, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode prompts: %w", err)
+		}
+		if err := os.WriteFile(output, append(data, '\n'), 0644); err != nil {
+			return fmt.Errorf("failed to write prompts: %w", err)
+		}
+		fmt.Printf("💾 Prompts saved to %s\n", output)
+	}
+
+	for _, prompt := range rendered {
+		if output == "" {
+			fmt.Printf("\n🧪 Dry run: %s (%s)\n", prompt.Provider, prompt.Model)
+			if prompt.System != "" {
+				fmt.Println(strings.Repeat("─", 60))
+				fmt.Println("SYSTEM:")
+				fmt.Println(prompt.System)
+			}
+			fmt.Println(strings.Repeat("─", 60))
+			fmt.Println("USER:")
+			fmt.Println(prompt.User)
+			fmt.Println(strings.Repeat("─", 60))
+		}
+
+		fmt.Printf("📏 %s: ~%d input tokens, up to %d output tokens, %d token context window\n",
+			prompt.Provider, prompt.InputTokens, prompt.MaxOutputTokens, prompt.ContextWindow)
+		if prompt.Batches > 0 {
+			fmt.Printf("📦 Too large for one request: would be summarized in %d batches, then combined\n", prompt.Batches)
+		}
+		if prompt.EstimatedCost != nil {
+			fmt.Printf("💰 Estimated cost: up to $%.4f\n", *prompt.EstimatedCost)
+		} else {
+			fmt.Printf("💰 Estimated cost: unknown for model %s\n", prompt.Model)
+		}
+	}
+	return nil
+}
+
+// parseProviderChain splits a comma separated --provider value into an ordered fallback chain
+func parseProviderChain(value string) ([]llm.Provider, error) {
+	var providers []llm.Provider
+	seen := map[llm.Provider]bool{}
+	for _, name := range strings.Split(value, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if err := llm.ValidateProvider(name); err != nil {
+			return nil, err
+		}
+		if p := llm.Provider(name); !seen[p] {
+			seen[p] = true
+			providers = append(providers, p)
+		}
+	}
+	if len(providers) == 0 {
+		return nil, fmt.Errorf("no provider given")
+	}
+	return providers, nil
+}
+
+func joinProviders(providers []llm.Provider, sep string) string {
+	names := make([]string, len(providers))
+	for i, p := range providers {
+		names[i] = string(p)
+	}
+	return strings.Join(names, sep)
+}
+
+// summarizeError adds an actionable hint to classified provider errors
+func summarizeError(err error) error {
+	var llmErr *llm.Error
+	if errors.As(err, &llmErr) {
+		if hint := llmErr.Hint(); hint != "" {
+			return fmt.Errorf("❌ %w\n💡 %s", llmErr, hint)
+		}
+	}
+	return fmt.Errorf("failed to generate summary: %w", err)
+}
+
+// displaySummaryHeader prints the title line shown above the summary text
+func displaySummaryHeader(platform llm.Platform) {
 	icons := map[llm.Platform]string{
 		"blog":      "📝",
 		"twitter/X": "🐦",
@@ -187,10 +316,15 @@ func displaySummary(response *llm.SummaryResponse, platform llm.Platform) {
 
 	fmt.Printf("\n%s %s Summary:\n", icon, platformTitle)
 	fmt.Println(strings.Repeat("─", 60))
-	fmt.Println(response.Summary)
+}
 
+// displaySummaryFooter prints the statistics shown below the summary text
+func displaySummaryFooter(response *llm.SummaryResponse, platform llm.Platform) {
 	// Show statistics using helper methods
 	fmt.Printf("\n📊 %s\n", response.GetStats())
+	if response.Provider != "" {
+		fmt.Printf("🤖 Generated by %s\n", response.Provider)
+	}
 
 	if !response.MeetsRequirements() {
 		fmt.Printf("⚠️ Warning: Summary may not meet %s platform requirements\n", platform)
@@ -202,10 +336,11 @@ func saveSummaryToFile(response *llm.SummaryResponse, filename string) error {
 	caser := cases.Title(language.English)
 	platformTitle := caser.String(string(response.Platform))
 
-	content := fmt.Sprintf("# %s Summary\n\n%s\n\n---\nGenerated by [GitStory](https://github.com/frfahim/gitstory)\nPlatform: %s\nStats: %s\n",
+	content := fmt.Sprintf("# %s Summary\n\n%s\n\n---\nGenerated by [GitStory](https://github.com/frfahim/gitstory)\nPlatform: %s\nProvider: %s\nStats: %s\n",
 		platformTitle,
 		response.Summary,
 		response.Platform,
+		response.Provider,
 		response.GetStats())
 
 	return os.WriteFile(filename, []byte(content), 0644)
@@ -215,26 +350,28 @@ func init() {
 	rootCmd.AddCommand(summarizeCmd)
 
 	// Provider and platform options
-	summarizeCmd.Flags().String("provider", "", "LLM provider (openai, gemini, claude)")
+	addProviderFlags(summarizeCmd)
 	summarizeCmd.Flags().String("platform", "", "Target platform (twitter/X, linkedin, blog, technical, notes)")
 
 	// Commit selection options
 	summarizeCmd.Flags().String("numbers", "", "Number of latest commits to summarize (e.g. 5)")
 	summarizeCmd.Flags().Bool("unique", false, "Summarize only commits unique to current branch")
-	summarizeCmd.Flags().String("base", "main", "Base branch for unique commit comparison")
+	summarizeCmd.Flags().String("base", "auto", "Base for --unique: a branch, remote branch (origin/main), tag or SHA (default: auto-detect from origin/HEAD, then main/master)")
+	addCommitFilterFlags(summarizeCmd)
+	addContentFlags(summarizeCmd)
+	addCacheFlag(summarizeCmd)
 
 	// Content options
 	summarizeCmd.Flags().String("context", "", "Additional context to improve the summary")
+	addRedactFlags(summarizeCmd)
 
 	// Output options
 	summarizeCmd.Flags().String("output", "", "Save summary to file (optional)")
+	summarizeCmd.Flags().Bool("dry-run", false, "Print the prompts and estimated tokens and cost without calling a provider (JSON with --output)")
+	summarizeCmd.Flags().Bool("stream", true, "Print the summary as it is generated (--stream=false waits for the full response)")
 
 	// Shell completion
 	summarizeCmd.RegisterFlagCompletionFunc("platform", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		return []string{"twitter", "X", "linkedin", "blog", "technical", "notes"}, cobra.ShellCompDirectiveNoFileComp
 	})
-
-	summarizeCmd.RegisterFlagCompletionFunc("provider", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-		return []string{"openai", "gemini", "claude"}, cobra.ShellCompDirectiveNoFileComp
-	})
 }
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/frfahim/gitstory/internal/conventional"
 	"github.com/frfahim/gitstory/internal/types"
 )
 
@@ -12,6 +13,9 @@ func SummarizeCommits(commits []types.CommitData) string {
 	if len(commits) == 0 {
 		return "No commits to summarize."
 	}
+	if conventional.Any(commits) {
+		return summarizeByType(commits)
+	}
 	var lines []string
 	for _, c := range commits {
 		firstLine := strings.SplitN(c.Message, "\n", 2)[0]
@@ -19,3 +23,35 @@ func SummarizeCommits(commits []types.CommitData) string {
 	}
 	return strings.Join(lines, "\n")
 }
+
+// summarizeByType groups Conventional Commits by type, breaking changes first
+func summarizeByType(commits []types.CommitData) string {
+	var sections []string
+	for _, section := range conventional.Group(commits, conventional.TypeOrder...) {
+		lines := []string{fmt.Sprintf("%s (%d):", section.Title, len(section.Commits))}
+		for _, c := range section.Commits {
+			// Mixed sections keep the type prefix, e.g. "feat(api)!: drop v1"
+			lines = append(lines, "- "+describeCommit(c, section.Type == conventional.Breaking || section.Type == conventional.Other))
+		}
+		sections = append(sections, strings.Join(lines, "\n"))
+	}
+	return strings.Join(sections, "\n\n")
+}
+
+// describeCommit renders "hash: scope: subject", or "hash: first line" when
+// withType is set or the message isn't a Conventional Commit
+func describeCommit(c types.CommitData, withType bool) string {
+	cc := c.Conventional
+	if cc == nil || withType {
+		line := fmt.Sprintf("%s: %s", c.Hash, strings.SplitN(c.Message, "\n", 2)[0])
+		if cc != nil && cc.BreakingNote != "" {
+			line += " (" + cc.BreakingNote + ")"
+		}
+		return line
+	}
+	line := cc.Subject
+	if cc.Scope != "" {
+		line = cc.Scope + ": " + line
+	}
+	return fmt.Sprintf("%s: %s", c.Hash, line)
+}
@@ -0,0 +1,401 @@
+// Package changelog renders commits as Keep a Changelog release sections
+// (https://keepachangelog.com) and inserts them into an existing CHANGELOG.md.
+package changelog
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/frfahim/gitstory/internal/types"
+)
+
+// Unreleased is the version of the section for changes since the last release
+const Unreleased = "Unreleased"
+
+// Keep a Changelog categories, in the order they appear in a release
+const (
+	Added      = "Added"
+	Changed    = "Changed"
+	Deprecated = "Deprecated"
+	Removed    = "Removed"
+	Fixed      = "Fixed"
+	Security   = "Security"
+)
+
+// Categories lists the categories in the order they appear in a release
+var Categories = []string{Added, Changed, Deprecated, Removed, Fixed, Security}
+
+// categoryOfType maps commit types to categories. Types that don't change
+// what users get (docs, test, build, ci, style, chore) are left out.
+var categoryOfType = map[string]string{
+	"feat":       Added,
+	"fix":        Fixed,
+	"perf":       Changed,
+	"refactor":   Changed,
+	"revert":     Changed,
+	"deprecate":  Deprecated,
+	"deprecated": Deprecated,
+	"remove":     Removed,
+	"removed":    Removed,
+	"security":   Security,
+	"sec":        Security,
+}
+
+// Header starts a new changelog
+const Header = `# Changelog
+
+All notable changes to this project will be documented in this file.
+
+The format is based on [Keep a Changelog](https://keepachangelog.com/en/1.1.0/),
+and this project adheres to [Semantic Versioning](https://semver.org/spec/v2.0.0.html).
+`
+
+// Release is the commits of one version, newest first
+type Release struct {
+	// Version is the release's version without a "v" prefix, or Unreleased
+	Version string
+	Date    time.Time
+	Commits []types.CommitData
+}
+
+// Heading returns the release's "## [version] - date" line
+func (r Release) Heading() string {
+	if r.Version == Unreleased || r.Date.IsZero() {
+		return fmt.Sprintf("## [%s]", r.Version)
+	}
+	return fmt.Sprintf("## [%s] - %s", r.Version, r.Date.Format("2006-01-02"))
+}
+
+// Section is one category of a release and its entries
+type Section struct {
+	Category string
+	Entries  []string
+}
+
+// Sections sorts commits into categories, in the order of Categories. Breaking
+// changes lead Changed, and commits that don't follow Conventional Commits are
+// listed under Changed by their first line. Merge commits are left out.
+func Sections(commits []types.CommitData) []Section {
+	byCategory := map[string][]string{}
+	var breaking []string
+	for _, commit := range commits {
+		cc := commit.Conventional
+		if cc == nil {
+			subject := strings.TrimSpace(strings.SplitN(commit.Message, "\n", 2)[0])
+			if subject != "" && !strings.HasPrefix(subject, "Merge ") {
+				byCategory[Changed] = append(byCategory[Changed], entry("", subject, commit.Hash))
+			}
+			continue
+		}
+		if cc.Breaking {
+			note := cc.BreakingNote
+			if note == "" {
+				note = cc.Subject
+			}
+			breaking = append(breaking, entry(cc.Scope, "**Breaking:** "+note, commit.Hash))
+			continue
+		}
+		category := categoryOfType[cc.Type]
+		if cc.Type == "fix" && strings.EqualFold(cc.Scope, "security") {
+			category = Security
+		}
+		if category != "" {
+			byCategory[category] = append(byCategory[category], entry(cc.Scope, cc.Subject, commit.Hash))
+		}
+	}
+	byCategory[Changed] = append(breaking, byCategory[Changed]...)
+
+	var sections []Section
+	for _, category := range Categories {
+		if len(byCategory[category]) > 0 {
+			sections = append(sections, Section{Category: category, Entries: byCategory[category]})
+		}
+	}
+	return sections
+}
+
+// entry formats one bullet, e.g. "**auth:** handle expired tokens (abc1234)"
+func entry(scope, text, hash string) string {
+	if scope != "" {
+		text = fmt.Sprintf("**%s:** %s", scope, text)
+	}
+	if hash != "" {
+		text = fmt.Sprintf("%s (%s)", text, hash)
+	}
+	return text
+}
+
+// Render formats a release as a Keep a Changelog section
+func Render(release Release) string {
+	var b strings.Builder
+	b.WriteString(release.Heading())
+	b.WriteString("\n")
+	for _, section := range Sections(release.Commits) {
+		fmt.Fprintf(&b, "\n### %s\n\n", section.Category)
+		for _, entry := range section.Entries {
+			fmt.Fprintf(&b, "- %s\n", entry)
+		}
+	}
+	return b.String()
+}
+
+// heading matches a release heading such as "## [1.2.0] - 2024-05-01",
+// "## 1.2.0" or "## [Unreleased]" and captures its version
+var heading = regexp.MustCompile(`^##\s+\[?v?([^\]\s]+)\]?`)
+
+// linkDefinition matches a link reference such as "[1.2.0]: https://..."
+var linkDefinition = regexp.MustCompile(`^\[[^\]]+\]:\s`)
+
+// Has reports whether changelog has a section for version
+func Has(changelog, version string) bool {
+	return headingLine(strings.Split(changelog, "\n"), version) >= 0
+}
+
+// Pending returns the releases, in order, whose sections Update would
+// generate: those missing from changelog, and Unreleased when its section was
+// generated from other commits than those since the last release
+func Pending(changelog string, releases []Release) []Release {
+	lines := strings.Split(changelog, "\n")
+	var pending []Release
+	for _, release := range releases {
+		line := headingLine(lines, release.Version)
+		if line < 0 || release.Version == Unreleased && unreleasedStale(lines, line, release) {
+			pending = append(pending, release)
+		}
+	}
+	return pending
+}
+
+// Changes lists the sections Update changed, by version
+type Changes struct {
+	Added   []string
+	Updated []string
+	Removed []string
+}
+
+// Empty reports whether Update left the changelog as it was
+func (c Changes) Empty() bool {
+	return len(c.Added)+len(c.Updated)+len(c.Removed) == 0
+}
+
+// Update brings changelog up to date with releases, newest first. Missing
+// releases are inserted as Insert does and released sections are never
+// touched. The [Unreleased] section is generated: it is regenerated when the
+// commits since the last release change, and removed when
+// there are none, as the release that covers them now lists them.
+func Update(changelog string, releases []Release, render func(Release) string) (string, Changes) {
+	var changes Changes
+	lines := strings.Split(strings.TrimRight(changelog, "\n"), "\n")
+	if line := headingLine(lines, Unreleased); line >= 0 {
+		unreleased, ok := findRelease(releases, Unreleased)
+		switch {
+		case !ok:
+			lines = replaceSection(lines, line, nil)
+			changes.Removed = append(changes.Removed, Unreleased)
+		case unreleasedStale(lines, line, unreleased):
+			lines = replaceSection(lines, line, renderSection(unreleased, render))
+			changes.Updated = append(changes.Updated, Unreleased)
+		}
+		changelog = strings.Join(lines, "\n") + "\n"
+	}
+
+	updated, added := Insert(changelog, releases, render)
+	changes.Added = added
+	return updated, changes
+}
+
+// findRelease returns the release of version
+func findRelease(releases []Release, version string) (Release, bool) {
+	for _, release := range releases {
+		if release.Version == version {
+			return release, true
+		}
+	}
+	return Release{}, false
+}
+
+// commitsMarker records, under the [Unreleased] heading, the commits the
+// section was generated from, e.g. "<!-- commits: abc1234 def5678 -->"
+var commitsMarker = regexp.MustCompile(`^<!--\s*commits:([^>]*)-->$`)
+
+// unreleasedStale reports whether the [Unreleased] section at line was
+// generated from other commits than those listed in release. The section's
+// text is not compared, so rewritten and hand-edited entries are kept as long
+// as the commits stay the same. Sections without a commits marker are stale
+// when a listed commit's hash doesn't appear in them.
+func unreleasedStale(lines []string, line int, release Release) bool {
+	hashes := listedHashes(release.Commits)
+	section := lines[line:sectionEnd(lines, line)]
+	for _, text := range section {
+		if m := commitsMarker.FindStringSubmatch(strings.TrimSpace(text)); m != nil {
+			return strings.Join(strings.Fields(m[1]), " ") != strings.Join(hashes, " ")
+		}
+	}
+	current := strings.Join(section, "\n")
+	for _, hash := range hashes {
+		if !strings.Contains(current, "("+hash+")") {
+			return true
+		}
+	}
+	return false
+}
+
+// listedHashes returns the hashes of the commits that get an entry
+func listedHashes(commits []types.CommitData) []string {
+	var hashes []string
+	for _, commit := range commits {
+		if commit.Hash != "" && HasEntries([]types.CommitData{commit}) {
+			hashes = append(hashes, commit.Hash)
+		}
+	}
+	return hashes
+}
+
+// renderSection renders release as lines; an [Unreleased] section records
+// its commits below the heading so Update can tell when it is stale
+func renderSection(release Release, render func(Release) string) []string {
+	section := strings.Split(strings.TrimRight(render(release), "\n"), "\n")
+	hashes := listedHashes(release.Commits)
+	if release.Version != Unreleased || len(hashes) == 0 {
+		return section
+	}
+	marker := fmt.Sprintf("<!-- commits: %s -->", strings.Join(hashes, " "))
+	return append([]string{section[0], marker}, section[1:]...)
+}
+
+// Insert adds the sections of releases, newest first as in a changelog,
+// whose versions aren't in changelog yet. Everything already in the file is
+// kept as it is, manual edits included: a new release goes above the next
+// older release that is already listed, or below the next newer one. An
+// empty changelog starts with Header. Insert returns the updated changelog
+// and the versions it added. releases should hold every release, not only
+// the missing ones, so each new section can be placed among its neighbours.
+func Insert(changelog string, releases []Release, render func(Release) string) (string, []string) {
+	if strings.TrimSpace(changelog) == "" {
+		changelog = Header
+	}
+	lines := strings.Split(strings.TrimRight(changelog, "\n"), "\n")
+
+	var added []string
+	for i, release := range releases {
+		if headingLine(lines, release.Version) >= 0 {
+			continue
+		}
+		lines = insertSection(lines, renderSection(release, render), position(lines, releases, i))
+		added = append(added, release.Version)
+	}
+	return strings.Join(lines, "\n") + "\n", added
+}
+
+// position returns the line before which the i-th release goes
+func position(lines []string, releases []Release, i int) int {
+	for _, older := range releases[i+1:] {
+		if line := headingLine(lines, older.Version); line >= 0 {
+			return line
+		}
+	}
+	for j := i - 1; j >= 0; j-- {
+		if line := headingLine(lines, releases[j].Version); line >= 0 {
+			return sectionEnd(lines, line)
+		}
+	}
+	// None of its neighbours are listed: above the first release that isn't
+	// Unreleased, else after the last section
+	last := 0
+	for line, text := range lines {
+		if m := heading.FindStringSubmatch(text); m != nil {
+			if !strings.EqualFold(m[1], Unreleased) {
+				return line
+			}
+			last = line
+		}
+	}
+	return sectionEnd(lines, last)
+}
+
+// headingLine returns the line of version's heading, or -1
+func headingLine(lines []string, version string) int {
+	for i, line := range lines {
+		if m := heading.FindStringSubmatch(line); m != nil && strings.EqualFold(m[1], version) {
+			return i
+		}
+	}
+	return -1
+}
+
+// sectionEnd returns the line after the section starting at start: the next
+// release heading, or the link references at the end of the file
+func sectionEnd(lines []string, start int) int {
+	for i := start + 1; i < len(lines); i++ {
+		if strings.HasPrefix(lines[i], "## ") {
+			return i
+		}
+	}
+	end := len(lines)
+	for end > start+1 && (strings.TrimSpace(lines[end-1]) == "" || linkDefinition.MatchString(lines[end-1])) {
+		end--
+	}
+	return end
+}
+
+// replaceSection replaces the section starting at start with section, or
+// removes it when section is nil
+func replaceSection(lines []string, start int, section []string) []string {
+	rest := append(append([]string{}, lines[:start]...), lines[sectionEnd(lines, start):]...)
+	if section != nil {
+		return insertSection(rest, section, start)
+	}
+	before := rest[:start]
+	for len(before) > 0 && strings.TrimSpace(before[len(before)-1]) == "" {
+		before = before[:len(before)-1]
+	}
+	after := rest[start:]
+	for len(after) > 0 && strings.TrimSpace(after[0]) == "" {
+		after = after[1:]
+	}
+	updated := append([]string{}, before...)
+	if len(after) > 0 {
+		updated = append(append(updated, ""), after...)
+	}
+	return updated
+}
+
+// insertSection inserts section before line at, separated from its
+// neighbours by one blank line
+func insertSection(lines, section []string, at int) []string {
+	before := lines[:at]
+	for len(before) > 0 && strings.TrimSpace(before[len(before)-1]) == "" {
+		before = before[:len(before)-1]
+	}
+	after := lines[at:]
+	for len(after) > 0 && strings.TrimSpace(after[0]) == "" {
+		after = after[1:]
+	}
+
+	updated := append([]string{}, before...)
+	updated = append(updated, "")
+	updated = append(updated, section...)
+	if len(after) > 0 {
+		updated = append(updated, "")
+	}
+	return append(updated, after...)
+}
+
+// versionTag matches release tags such as v1.2.0, 2.0 or 1.0.0-rc.1
+var versionTag = regexp.MustCompile(`^[vV]?[0-9]+(\.[0-9]+)*([-+.].*)?$`)
+
+// ReleaseVersion returns the version of a release tag, without its "v"
+// prefix; ok is false when tag doesn't look like a version
+func ReleaseVersion(tag string) (version string, ok bool) {
+	if !versionTag.MatchString(tag) {
+		return "", false
+	}
+	return strings.TrimLeft(tag, "vV"), true
+}
+
+// HasEntries reports whether any of commits would be listed in a release
+func HasEntries(commits []types.CommitData) bool {
+	return len(Sections(commits)) > 0
+}
@@ -0,0 +1,226 @@
+package changelog
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/frfahim/gitstory/internal/conventional"
+	"github.com/frfahim/gitstory/internal/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func commit(hash, message string) types.CommitData {
+	return types.CommitData{Hash: hash, Message: message, Conventional: conventional.Parse(message)}
+}
+
+func TestRender(t *testing.T) {
+	release := Release{
+		Version: "1.2.0",
+		Date:    time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+		Commits: []types.CommitData{
+			commit("a1", "fix(auth): handle expired tokens"),
+			commit("b2", "feat: add SSO"),
+			commit("c3", "chore: bump deps"),
+			commit("d4", "refactor!: rename config keys\n\nBREAKING CHANGE: env is now environment"),
+			commit("e5", "fix(security): escape user names"),
+			commit("f6", "Tweak the logo"),
+			commit("g7", "Merge branch 'main' into feature"),
+		},
+	}
+
+	assert.Equal(t, `## [1.2.0] - 2024-05-01
+
+### Added
+
+- add SSO (b2)
+
+### Changed
+
+- **Breaking:** env is now environment (d4)
+- Tweak the logo (f6)
+
+### Fixed
+
+- **auth:** handle expired tokens (a1)
+
+### Security
+
+- **security:** escape user names (e5)
+`, Render(release))
+
+	assert.Equal(t, "## [Unreleased]", Release{Version: Unreleased, Date: time.Now()}.Heading())
+	assert.False(t, HasEntries([]types.CommitData{commit("a1", "docs: typo"), commit("b2", "ci: cache")}))
+}
+
+func TestInsert(t *testing.T) {
+	render := func(r Release) string { return "## [" + r.Version + "]\n\n- generated " + r.Version + "\n" }
+	releases := []Release{{Version: Unreleased}, {Version: "1.2.0"}, {Version: "1.1.0"}, {Version: "1.0.0"}}
+
+	existing := `# Changelog
+
+Hand-written intro.
+
+## [1.1.0] - 2024-04-01
+
+- Edited by hand
+
+[1.1.0]: https://example.com/compare/v1.0.0...v1.1.0
+`
+	updated, added := Insert(existing, releases, render)
+	assert.Equal(t, []string{Unreleased, "1.2.0", "1.0.0"}, added)
+	assert.Equal(t, `# Changelog
+
+Hand-written intro.
+
+## [Unreleased]
+
+- generated Unreleased
+
+## [1.2.0]
+
+- generated 1.2.0
+
+## [1.1.0] - 2024-04-01
+
+- Edited by hand
+
+## [1.0.0]
+
+- generated 1.0.0
+
+[1.1.0]: https://example.com/compare/v1.0.0...v1.1.0
+`, updated)
+
+	again, added := Insert(updated, releases, render)
+	assert.Empty(t, added)
+	assert.Equal(t, updated, again)
+
+	// A new changelog starts with the header
+	created, _ := Insert("", releases[1:2], render)
+	assert.Equal(t, Header+"\n## [1.2.0]\n\n- generated 1.2.0\n", created)
+}
+
+func TestReleaseVersion(t *testing.T) {
+	tests := []struct {
+		tag     string
+		version string
+		ok      bool
+	}{
+		{"v1.2.0", "1.2.0", true},
+		{"2.0", "2.0", true},
+		{"v1.0.0-rc.1", "1.0.0-rc.1", true},
+		{"release-1", "", false},
+		{"vnext", "", false},
+	}
+	for _, tt := range tests {
+		version, ok := ReleaseVersion(tt.tag)
+		assert.Equal(t, tt.version, version, tt.tag)
+		assert.Equal(t, tt.ok, ok, tt.tag)
+	}
+}
+
+func TestPending_Backfill(t *testing.T) {
+	// The changelog command generates the pending releases, then updates the file with all of them
+	releases := []Release{{Version: "1.2.0"}, {Version: "1.1.0"}, {Version: "1.0.0"}}
+	existing := "# Changelog\n\n## [1.2.0]\n\n- newest\n\n## [1.0.0]\n\n- oldest\n"
+
+	pending := Pending(existing, releases)
+	assert.Equal(t, []Release{{Version: "1.1.0"}}, pending)
+
+	rendered := map[string]string{}
+	for _, r := range pending {
+		rendered[r.Version] = "## [" + r.Version + "]\n\n- backfilled\n"
+	}
+	updated, changes := Update(existing, releases, func(r Release) string { return rendered[r.Version] })
+	assert.Equal(t, Changes{Added: []string{"1.1.0"}}, changes)
+	assert.Equal(t, "# Changelog\n\n## [1.2.0]\n\n- newest\n\n## [1.1.0]\n\n- backfilled\n\n## [1.0.0]\n\n- oldest\n", updated)
+}
+
+func TestUpdate_Unreleased(t *testing.T) {
+	v100 := Release{Version: "1.0.0", Commits: []types.CommitData{commit("a1", "feat: first")}}
+	updated, _ := Update("", []Release{
+		{Version: Unreleased, Commits: []types.CommitData{commit("b2", "feat: second")}},
+		v100,
+	}, Render)
+
+	// New commits regenerate [Unreleased]
+	unreleased := Release{Version: Unreleased, Commits: []types.CommitData{commit("c3", "fix: third"), commit("b2", "feat: second")}}
+	releases := []Release{unreleased, v100}
+	assert.Equal(t, []Release{unreleased}, Pending(updated, releases))
+	updated, changes := Update(updated, releases, Render)
+	assert.Equal(t, Changes{Updated: []string{Unreleased}}, changes)
+	assert.Equal(t, Header+`
+## [Unreleased]
+<!-- commits: c3 b2 -->
+
+### Added
+
+- second (b2)
+
+### Fixed
+
+- third (c3)
+
+## [1.0.0]
+
+### Added
+
+- first (a1)
+`, updated)
+
+	again, changes := Update(updated, releases, Render)
+	assert.True(t, changes.Empty())
+	assert.Equal(t, updated, again)
+
+	// Once they are released, [Unreleased] makes way for the release
+	v110 := Release{Version: "1.1.0", Commits: unreleased.Commits}
+	releases = []Release{v110, v100}
+	assert.Equal(t, []Release{v110}, Pending(updated, releases))
+	updated, changes = Update(updated, releases, Render)
+	assert.Equal(t, Changes{Added: []string{"1.1.0"}, Removed: []string{Unreleased}}, changes)
+	assert.Equal(t, Header+`
+## [1.1.0]
+
+### Added
+
+- second (b2)
+
+### Fixed
+
+- third (c3)
+
+## [1.0.0]
+
+### Added
+
+- first (a1)
+`, updated)
+}
+
+func TestUpdate_EditedUnreleased(t *testing.T) {
+	unreleased := Release{Version: Unreleased, Commits: []types.CommitData{commit("b2", "feat: second"), commit("a1", "chore: tidy")}}
+	releases := []Release{unreleased}
+	updated, _ := Update("", releases, Render)
+
+	// Entries rewritten by the LLM or by hand are kept while the commits stay the same
+	edited := strings.Replace(updated, "- second (b2)", "- A second way to do things", 1)
+	assert.Empty(t, Pending(edited, releases))
+	again, changes := Update(edited, releases, Render)
+	assert.True(t, changes.Empty())
+	assert.Equal(t, edited, again)
+
+	// Without the commits marker, a section listing every commit's hash is kept
+	unmarked := strings.Replace(updated, "<!-- commits: b2 -->\n", "", 1)
+	unmarked = strings.Replace(unmarked, "- second (b2)", "- Second, edited (b2)", 1)
+	assert.Empty(t, Pending(unmarked, releases))
+
+	// A new commit regenerates it
+	unreleased.Commits = append([]types.CommitData{commit("c3", "fix: third")}, unreleased.Commits...)
+	releases = []Release{unreleased}
+	assert.Equal(t, releases, Pending(edited, releases))
+	regenerated, changes := Update(edited, releases, Render)
+	assert.Equal(t, Changes{Updated: []string{Unreleased}}, changes)
+	assert.Contains(t, regenerated, "<!-- commits: c3 b2 -->\n")
+	assert.NotContains(t, regenerated, "A second way")
+}
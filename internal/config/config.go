@@ -0,0 +1,353 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// RepoConfigFile is the repo-level config file, looked up at the repository root
+	RepoConfigFile = ".gitstory.yaml"
+	// ProfileEnvVar selects a named profile when --profile isn't given
+	ProfileEnvVar = "GITSTORY_PROFILE"
+	// ConfigEnvVar overrides the location of the user-level config file
+	ConfigEnvVar = "GITSTORY_CONFIG"
+)
+
+// Keys lists the settings a profile can hold, in display order
+var Keys = []string{"provider", "model", "platform", "base", "number", "context", "excludes", "content_excludes", "redact", "output"}
+
+// Profile is a set of defaults for the CLI flags
+type Profile struct {
+	Provider string   `yaml:"provider,omitempty"`
+	Model    string   `yaml:"model,omitempty"`
+	Platform string   `yaml:"platform,omitempty"`
+	Base     string   `yaml:"base,omitempty"`
+	Number   int      `yaml:"number,omitempty"`
+	Context  string   `yaml:"context,omitempty"`
+	Excludes []string `yaml:"excludes,omitempty"`
+	// ContentExcludes are files counted in the stats whose changes aren't sent
+	ContentExcludes []string `yaml:"content_excludes,omitempty"`
+	Redact          []string `yaml:"redact,omitempty"`
+	Output          string   `yaml:"output,omitempty"`
+}
+
+// File is the on-disk layout shared by the user and repo config files:
+// top-level defaults, an optional default profile and named profiles
+type File struct {
+	DefaultProfile string `yaml:"default_profile,omitempty"`
+	Profile        `yaml:",inline"`
+	Profiles       map[string]Profile `yaml:"profiles,omitempty"`
+
+	// document is the file as read, so Save can keep its comments, key
+	// order and keys it doesn't know
+	document *yaml.Node
+}
+
+// Get returns the string form of a key and whether it is set. Lists are
+// given as YAML flow sequences, e.g. [vendor/, '*.pb.go'], which Set reads back.
+func (p Profile) Get(key string) (string, bool) {
+	var value string
+	switch key {
+	case "provider":
+		value = p.Provider
+	case "model":
+		value = p.Model
+	case "platform":
+		value = p.Platform
+	case "base":
+		value = p.Base
+	case "number":
+		if p.Number > 0 {
+			value = strconv.Itoa(p.Number)
+		}
+	case "context":
+		value = p.Context
+	case "excludes", "content_excludes", "redact":
+		value = formatList(*p.list(key))
+	case "output":
+		value = p.Output
+	}
+	return value, value != ""
+}
+
+// Set assigns a key from its string form; an empty value clears it. Lists
+// take a YAML or JSON list such as [vendor/, "*.{js,ts}"]; other values are
+// split on commas, except redact patterns, which are split on newlines
+// because regexes may contain commas.
+func (p *Profile) Set(key, value string) error {
+	value = strings.TrimSpace(value)
+	switch key {
+	case "provider":
+		p.Provider = value
+	case "model":
+		p.Model = value
+	case "platform":
+		p.Platform = value
+	case "base":
+		p.Base = value
+	case "number":
+		if value == "" {
+			p.Number = 0
+			return nil
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 {
+			return fmt.Errorf("number must be a positive integer, got '%s'", value)
+		}
+		p.Number = n
+	case "context":
+		p.Context = value
+	case "excludes", "content_excludes":
+		*p.list(key) = parseList(value, ",")
+	case "redact":
+		p.Redact = parseList(value, "\n")
+	case "output":
+		p.Output = value
+	default:
+		return fmt.Errorf("unknown config key '%s'. Supported: %s", key, strings.Join(Keys, ", "))
+	}
+	return nil
+}
+
+// SetList assigns the items of a list key as given, without splitting them
+func (p *Profile) SetList(key string, items []string) error {
+	list := p.list(key)
+	if list == nil {
+		for _, known := range Keys {
+			if key == known {
+				return fmt.Errorf("%s takes a single value", key)
+			}
+		}
+		return fmt.Errorf("unknown config key '%s'. Supported: %s", key, strings.Join(Keys, ", "))
+	}
+	*list = nil
+	for _, item := range items {
+		if item = strings.TrimSpace(item); item != "" {
+			*list = append(*list, item)
+		}
+	}
+	return nil
+}
+
+// list returns the field of a list key, or nil when key isn't a list
+func (p *Profile) list(key string) *[]string {
+	switch key {
+	case "excludes":
+		return &p.Excludes
+	case "content_excludes":
+		return &p.ContentExcludes
+	case "redact":
+		return &p.Redact
+	}
+	return nil
+}
+
+// formatList renders items as a YAML flow sequence, or "" when there are none
+func formatList(items []string) string {
+	if len(items) == 0 {
+		return ""
+	}
+	node := &yaml.Node{Kind: yaml.SequenceNode, Style: yaml.FlowStyle}
+	for _, item := range items {
+		node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: item})
+	}
+	data, err := yaml.Marshal(node)
+	if err != nil {
+		return strings.Join(items, ", ")
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// parseList reads a YAML or JSON list, or else splits value on sep
+func parseList(value, sep string) []string {
+	items := strings.Split(value, sep)
+	if strings.HasPrefix(value, "[") && strings.HasSuffix(value, "]") {
+		var parsed []string
+		if err := yaml.Unmarshal([]byte(value), &parsed); err == nil {
+			items = parsed
+		}
+	}
+	var list []string
+	for _, item := range items {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+// LoadFile reads a config file; a missing file yields an empty config
+func LoadFile(path string) (*File, error) {
+	f := &File{}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return f, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config %s: %w", path, err)
+	}
+	document := &yaml.Node{}
+	if err := yaml.Unmarshal(data, document); err != nil {
+		return nil, fmt.Errorf("failed to parse config %s: %w", path, err)
+	}
+	if err := document.Decode(f); err != nil {
+		return nil, fmt.Errorf("failed to parse config %s: %w", path, err)
+	}
+	f.document = document
+	return f, nil
+}
+
+// Save writes the config file, creating its directory if needed. A file
+// read by LoadFile is edited in place: its comments, key order and unknown
+// keys are kept, and only the settings that changed are rewritten.
+func (f *File) Save(path string) error {
+	updated := &yaml.Node{}
+	if err := updated.Encode(f); err != nil {
+		return fmt.Errorf("failed to encode config: %w", err)
+	}
+	document := updated
+	if f.document != nil && len(f.document.Content) == 1 && f.document.Content[0].Kind == yaml.MappingNode {
+		mergeMapping(f.document.Content[0], updated, fileLevel)
+		document = f.document
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(document); err != nil {
+		return fmt.Errorf("failed to encode config: %w", err)
+	}
+	data := buf.Bytes()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// Levels of nesting in a config file, for mergeMapping
+const (
+	fileLevel     = iota // top-level settings, default_profile and profiles
+	profilesLevel        // the profiles, by name
+	profileLevel         // the settings of one profile
+)
+
+// managedKey reports whether File manages key at the given level
+func managedKey(level int, key string) bool {
+	switch level {
+	case fileLevel:
+		if key == "default_profile" || key == "profiles" {
+			return true
+		}
+	case profilesLevel:
+		return true
+	}
+	for _, known := range Keys {
+		if key == known {
+			return true
+		}
+	}
+	return false
+}
+
+// mergeMapping updates the mapping node dst to hold src's values. Managed keys
+// missing from src are removed and new ones are appended; values that didn't
+// change are kept as they were, comments and quoting included; keys File
+// doesn't know are left alone.
+func mergeMapping(dst, src *yaml.Node, level int) {
+	values := map[string]*yaml.Node{}
+	var order []string
+	for i := 0; i+1 < len(src.Content); i += 2 {
+		values[src.Content[i].Value] = src.Content[i+1]
+		order = append(order, src.Content[i].Value)
+	}
+
+	var content []*yaml.Node
+	seen := map[string]bool{}
+	for i := 0; i+1 < len(dst.Content); i += 2 {
+		key, value := dst.Content[i], dst.Content[i+1]
+		seen[key.Value] = true
+		updated, ok := values[key.Value]
+		switch {
+		case !ok && managedKey(level, key.Value):
+			continue
+		case !ok || sameValue(value, updated):
+		case value.Kind == yaml.MappingNode && updated.Kind == yaml.MappingNode:
+			// The profiles, or the settings of one of them
+			mergeMapping(value, updated, level+1)
+		default:
+			updated.HeadComment, updated.LineComment, updated.FootComment = value.HeadComment, value.LineComment, value.FootComment
+			value = updated
+		}
+		content = append(content, key, value)
+	}
+	for _, key := range order {
+		if !seen[key] {
+			content = append(content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, values[key])
+		}
+	}
+	dst.Content = content
+}
+
+// sameValue reports whether two nodes hold the same data
+func sameValue(a, b *yaml.Node) bool {
+	var x, y any
+	if a.Decode(&x) != nil || b.Decode(&y) != nil {
+		return false
+	}
+	return reflect.DeepEqual(x, y)
+}
+
+// ProfileNames returns the names of the profiles defined in the file, sorted
+func (f *File) ProfileNames() []string {
+	var names []string
+	for name := range f.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// UserConfigPath returns $GITSTORY_CONFIG, or $XDG_CONFIG_HOME/gitstory/config.yaml
+// (~/.config/gitstory/config.yaml when XDG_CONFIG_HOME is unset)
+func UserConfigPath() (string, error) {
+	if path := os.Getenv(ConfigEnvVar); path != "" {
+		return path, nil
+	}
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to locate home directory: %w", err)
+		}
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, "gitstory", "config.yaml"), nil
+}
+
+// RepoConfigPath returns the .gitstory.yaml path at the root of the repository
+// containing dir, or "" when dir isn't inside a repository
+func RepoConfigPath(dir string) string {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return ""
+	}
+	for {
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return filepath.Join(dir, RepoConfigFile)
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
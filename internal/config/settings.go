@@ -0,0 +1,115 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Settings is the effective configuration after merging, lowest precedence first:
+//
+//  1. user config top-level defaults (~/.config/gitstory/config.yaml)
+//  2. user config selected profile
+//  3. repo config top-level defaults (.gitstory.yaml)
+//  4. repo config selected profile
+//  5. GITSTORY_<KEY> environment variables (e.g. GITSTORY_PROVIDER)
+//
+// Command-line flags override all of these.
+type Settings struct {
+	Profile
+	// ProfileName is the selected profile, empty when none
+	ProfileName string
+	// Sources records where each set key came from, e.g. "repo profile 'work'"
+	Sources map[string]string
+	// UserPath and RepoPath are the config files consulted ("" when there is no repo)
+	UserPath string
+	RepoPath string
+}
+
+// Load resolves the settings for a working directory. profileName selects a
+// named profile; when empty, GITSTORY_PROFILE and then the files'
+// default_profile are used.
+func Load(dir, profileName string) (*Settings, error) {
+	userPath, err := UserConfigPath()
+	if err != nil {
+		return nil, err
+	}
+	userFile, err := LoadFile(userPath)
+	if err != nil {
+		return nil, err
+	}
+
+	repoPath := RepoConfigPath(dir)
+	repoFile := &File{}
+	if repoPath != "" {
+		if repoFile, err = LoadFile(repoPath); err != nil {
+			return nil, err
+		}
+	}
+
+	if profileName == "" {
+		profileName = strings.TrimSpace(os.Getenv(ProfileEnvVar))
+	}
+	if profileName == "" {
+		profileName = repoFile.DefaultProfile
+	}
+	if profileName == "" {
+		profileName = userFile.DefaultProfile
+	}
+
+	s := &Settings{
+		ProfileName: profileName,
+		Sources:     map[string]string{},
+		UserPath:    userPath,
+		RepoPath:    repoPath,
+	}
+
+	s.merge(userFile.Profile, "user config")
+	userProfile, inUser := userFile.Profiles[profileName]
+	if inUser {
+		s.merge(userProfile, fmt.Sprintf("user profile '%s'", profileName))
+	}
+	s.merge(repoFile.Profile, "repo config")
+	repoProfile, inRepo := repoFile.Profiles[profileName]
+	if inRepo {
+		s.merge(repoProfile, fmt.Sprintf("repo profile '%s'", profileName))
+	}
+	if profileName != "" && !inUser && !inRepo {
+		return nil, fmt.Errorf("profile '%s' not found in %s or %s", profileName, userPath, RepoConfigFile)
+	}
+
+	for _, key := range Keys {
+		envKey := EnvVar(key)
+		if value := strings.TrimSpace(os.Getenv(envKey)); value != "" {
+			if err := s.Set(key, value); err != nil {
+				return nil, fmt.Errorf("invalid %s: %w", envKey, err)
+			}
+			s.Sources[key] = "env " + envKey
+		}
+	}
+
+	return s, nil
+}
+
+// EnvVar returns the environment variable that overrides a key, e.g. GITSTORY_PROVIDER
+func EnvVar(key string) string {
+	return "GITSTORY_" + strings.ToUpper(key)
+}
+
+// merge copies every key set in p over the current settings. Lists are
+// copied as they are, so items read from YAML are never split.
+func (s *Settings) merge(p Profile, source string) {
+	for _, key := range Keys {
+		if list := p.list(key); list != nil {
+			if len(*list) > 0 {
+				_ = s.SetList(key, *list)
+				s.Sources[key] = source
+			}
+			continue
+		}
+		if value, ok := p.Get(key); ok {
+			_ = s.Set(key, value)
+			s.Sources[key] = source
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+}
+
+func setupConfigDirs(t *testing.T) (userPath, repoDir string) {
+	dir := t.TempDir()
+	userPath = filepath.Join(dir, "user", "config.yaml")
+	repoDir = filepath.Join(dir, "repo")
+	require.NoError(t, os.MkdirAll(filepath.Join(repoDir, ".git"), 0755))
+
+	t.Setenv(ConfigEnvVar, userPath)
+	t.Setenv(ProfileEnvVar, "")
+	for _, key := range Keys {
+		t.Setenv(EnvVar(key), "")
+	}
+	return userPath, repoDir
+}
+
+func TestLoad_Precedence(t *testing.T) {
+	userPath, repoDir := setupConfigDirs(t)
+	writeFile(t, userPath, `
+provider: openai
+platform: twitter
+number: 7
+profiles:
+  work:
+    platform: linkedin
+    model: gpt-4o
+`)
+	writeFile(t, filepath.Join(repoDir, RepoConfigFile), `
+default_profile: work
+base: develop
+excludes: [vendor/]
+profiles:
+  work:
+    model: gpt-4o-mini
+`)
+	t.Setenv("GITSTORY_PROVIDER", "gemini")
+
+	// Lookup walks up to the repository root
+	subdir := filepath.Join(repoDir, "services", "billing")
+	require.NoError(t, os.MkdirAll(subdir, 0755))
+
+	s, err := Load(subdir, "")
+	require.NoError(t, err)
+
+	assert.Equal(t, "work", s.ProfileName)
+	assert.Equal(t, "gemini", s.Provider)
+	assert.Equal(t, "env GITSTORY_PROVIDER", s.Sources["provider"])
+	assert.Equal(t, "linkedin", s.Platform)
+	assert.Equal(t, "user profile 'work'", s.Sources["platform"])
+	assert.Equal(t, "gpt-4o-mini", s.Model)
+	assert.Equal(t, "repo profile 'work'", s.Sources["model"])
+	assert.Equal(t, "develop", s.Base)
+	assert.Equal(t, 7, s.Number)
+	assert.Equal(t, []string{"vendor/"}, s.Excludes)
+}
+
+func TestLoad_UnknownProfile(t *testing.T) {
+	_, repoDir := setupConfigDirs(t)
+
+	_, err := Load(repoDir, "missing")
+	assert.Error(t, err)
+}
+
+func TestFile_SetAndSave(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "config.yaml")
+
+	f, err := LoadFile(path)
+	require.NoError(t, err)
+	require.NoError(t, f.Set("excludes", "vendor/, *.pb.go"))
+	require.NoError(t, f.Set("number", "12"))
+	assert.Error(t, f.Set("number", "zero"))
+	assert.Error(t, f.Set("colour", "blue"))
+	require.NoError(t, f.Save(path))
+
+	loaded, err := LoadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"vendor/", "*.pb.go"}, loaded.Excludes)
+	assert.Equal(t, 12, loaded.Number)
+}
+
+func TestFile_SaveKeepsComments(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	writeFile(t, path, `# Team defaults
+platform: technical # for the wiki
+excludes: [vendor/, "*.pb.go"]
+team: platform-infra
+profiles:
+  # Release notes
+  release:
+    platform: blog
+    provider: claude
+`)
+
+	f, err := LoadFile(path)
+	require.NoError(t, err)
+	require.NoError(t, f.Set("platform", "note"))
+	require.NoError(t, f.Set("excludes", ""))
+	require.NoError(t, f.Set("number", "8"))
+	release := f.Profiles["release"]
+	require.NoError(t, release.Set("output", "RELEASE.md"))
+	f.Profiles["release"] = release
+	require.NoError(t, f.Save(path))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, `# Team defaults
+platform: note # for the wiki
+team: platform-infra
+profiles:
+  # Release notes
+  release:
+    platform: blog
+    provider: claude
+    output: RELEASE.md
+number: 8
+`, string(data))
+}
+
+func TestProfile_Lists(t *testing.T) {
+	userPath, repoDir := setupConfigDirs(t)
+	writeFile(t, userPath, `
+excludes: ["*.{js,ts}", vendor/]
+redact: ['ACME-[0-9]{6}', 'key=\w{2,8}']
+`)
+
+	// Patterns read from YAML are never split on their commas
+	s, err := Load(repoDir, "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"*.{js,ts}", "vendor/"}, s.Excludes)
+	assert.Equal(t, []string{"ACME-[0-9]{6}", `key=\w{2,8}`}, s.Redact)
+
+	// Get gives a list Set reads back as it was
+	for _, key := range []string{"excludes", "redact"} {
+		value, ok := s.Get(key)
+		require.True(t, ok)
+		var p Profile
+		require.NoError(t, p.Set(key, value))
+		assert.Equal(t, *s.list(key), *p.list(key), "%s = %s", key, value)
+	}
+
+	var p Profile
+	require.NoError(t, p.Set("content_excludes", `["*.{snap,golden}", fixtures/]`))
+	assert.Equal(t, []string{"*.{snap,golden}", "fixtures/"}, p.ContentExcludes)
+	require.NoError(t, p.Set("content_excludes", "fixtures/, testdata/"))
+	assert.Equal(t, []string{"fixtures/", "testdata/"}, p.ContentExcludes)
+	require.NoError(t, p.Set("redact", "[0-9a-f]{40}"))
+	assert.Equal(t, []string{"[0-9a-f]{40}"}, p.Redact)
+
+	require.NoError(t, p.SetList("excludes", []string{"*.{js,ts}", "a,b/"}))
+	assert.Equal(t, []string{"*.{js,ts}", "a,b/"}, p.Excludes)
+	assert.EqualError(t, p.SetList("provider", []string{"openai", "gemini"}), "provider takes a single value")
+	assert.Error(t, p.SetList("colour", []string{"red", "blue"}))
+}
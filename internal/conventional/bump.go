@@ -0,0 +1,47 @@
+package conventional
+
+import (
+	"github.com/frfahim/gitstory/internal/semver"
+	"github.com/frfahim/gitstory/internal/types"
+)
+
+// Bump is the version bump a set of commits calls for, with the commits behind it
+type Bump struct {
+	Level    semver.Level
+	Breaking []types.CommitData
+	Features []types.CommitData
+	// Fixes are fix, perf and revert commits
+	Fixes []types.CommitData
+}
+
+// patchTypes are the commit types that call for a patch release
+var patchTypes = map[string]bool{"fix": true, "perf": true, "revert": true}
+
+// RecommendBump returns the bump for commits: major for breaking changes,
+// minor for features, patch for fixes, and None when only other types (docs,
+// chore, ...) or commits that don't follow Conventional Commits are present
+func RecommendBump(commits []types.CommitData) Bump {
+	var bump Bump
+	for _, commit := range commits {
+		cc := commit.Conventional
+		switch {
+		case cc == nil:
+		case cc.Breaking:
+			bump.Breaking = append(bump.Breaking, commit)
+		case cc.Type == "feat":
+			bump.Features = append(bump.Features, commit)
+		case patchTypes[cc.Type]:
+			bump.Fixes = append(bump.Fixes, commit)
+		}
+	}
+
+	switch {
+	case len(bump.Breaking) > 0:
+		bump.Level = semver.Major
+	case len(bump.Features) > 0:
+		bump.Level = semver.Minor
+	case len(bump.Fixes) > 0:
+		bump.Level = semver.Patch
+	}
+	return bump
+}
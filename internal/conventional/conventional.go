@@ -0,0 +1,86 @@
+// Package conventional parses commit messages that follow the Conventional
+// Commits spec (https://www.conventionalcommits.org) and groups commits by type.
+package conventional
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/frfahim/gitstory/internal/types"
+)
+
+// header matches "type(scope)!: subject"
+var header = regexp.MustCompile(`^([A-Za-z][A-Za-z0-9_-]*)(?:\(([^()\r\n]*)\))?(!)?: +(\S.*)$`)
+
+// footer matches the first line of a footer: "Token: value" or "Token #value".
+// Tokens use "-" for spaces, except BREAKING CHANGE.
+var footer = regexp.MustCompile(`^(BREAKING CHANGE|BREAKING-CHANGE|[A-Za-z][A-Za-z0-9-]*)(?:: | #)(.*)$`)
+
+// Parse returns the structured form of a commit message, or nil when its
+// first line isn't a Conventional Commits header
+func Parse(message string) *types.ConventionalCommit {
+	lines := strings.Split(strings.ReplaceAll(strings.TrimSpace(message), "\r\n", "\n"), "\n")
+	match := header.FindStringSubmatch(strings.TrimSpace(lines[0]))
+	if match == nil {
+		return nil
+	}
+	commit := &types.ConventionalCommit{
+		Type:     strings.ToLower(match[1]),
+		Scope:    strings.TrimSpace(match[2]),
+		Breaking: match[3] == "!",
+		Subject:  strings.TrimSpace(match[4]),
+	}
+
+	rest := lines[1:]
+	start := footerStart(rest)
+	commit.Body = strings.TrimSpace(strings.Join(rest[:start], "\n"))
+
+	for _, line := range rest[start:] {
+		if m := footer.FindStringSubmatch(line); m != nil {
+			commit.Trailers = append(commit.Trailers, types.Trailer{Key: m[1], Value: m[2]})
+			continue
+		}
+		// Lines that don't start a footer continue the previous one's value
+		last := &commit.Trailers[len(commit.Trailers)-1]
+		last.Value += "\n" + line
+	}
+
+	trailers := commit.Trailers[:0]
+	for _, trailer := range commit.Trailers {
+		trailer.Value = strings.TrimSpace(trailer.Value)
+		if trailer.Key == "BREAKING CHANGE" || trailer.Key == "BREAKING-CHANGE" {
+			commit.Breaking = true
+			commit.BreakingNote = trailer.Value
+			continue
+		}
+		trailers = append(trailers, trailer)
+	}
+	commit.Trailers = trailers
+	if len(commit.Trailers) == 0 {
+		commit.Trailers = nil
+	}
+	return commit
+}
+
+// footerStart returns the index of the first footer line. Like git trailers,
+// footers are the last paragraph when every line in it starts a footer or,
+// indented, continues the previous one.
+func footerStart(lines []string) int {
+	end := len(lines)
+	for end > 0 && strings.TrimSpace(lines[end-1]) == "" {
+		end--
+	}
+	start := end
+	for start > 0 && strings.TrimSpace(lines[start-1]) != "" {
+		start--
+	}
+	if start == end || !footer.MatchString(lines[start]) {
+		return len(lines)
+	}
+	for _, line := range lines[start:end] {
+		if !footer.MatchString(line) && !strings.HasPrefix(line, " ") && !strings.HasPrefix(line, "\t") {
+			return len(lines)
+		}
+	}
+	return start
+}
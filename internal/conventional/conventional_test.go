@@ -0,0 +1,110 @@
+package conventional
+
+import (
+	"testing"
+
+	"github.com/frfahim/gitstory/internal/semver"
+	"github.com/frfahim/gitstory/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		message  string
+		expected *types.ConventionalCommit
+	}{
+		{"feat: add login", &types.ConventionalCommit{Type: "feat", Subject: "add login"}},
+		{"Fix(auth): handle expired tokens\n", &types.ConventionalCommit{Type: "fix", Scope: "auth", Subject: "handle expired tokens"}},
+		{"feat(api)!: drop v1 endpoints", &types.ConventionalCommit{Type: "feat", Scope: "api", Breaking: true, Subject: "drop v1 endpoints"}},
+		{
+			"refactor: split the parser\n\nThe parser was doing too much.\nNow it doesn't.\n\nRefs: #42\nSigned-off-by: Alice <alice@example.com>\n",
+			&types.ConventionalCommit{
+				Type: "refactor", Subject: "split the parser",
+				Body: "The parser was doing too much.\nNow it doesn't.",
+				Trailers: []types.Trailer{
+					{Key: "Refs", Value: "#42"},
+					{Key: "Signed-off-by", Value: "Alice <alice@example.com>"},
+				},
+			},
+		},
+		{
+			"chore: bump config format\n\nBREAKING CHANGE: the `env` key is now\n  `environment`\nReviewed-by: Bob\nCloses #7",
+			&types.ConventionalCommit{
+				Type: "chore", Subject: "bump config format", Breaking: true,
+				BreakingNote: "the `env` key is now\n  `environment`",
+				Trailers:     []types.Trailer{{Key: "Reviewed-by", Value: "Bob"}, {Key: "Closes", Value: "7"}},
+			},
+		},
+		{
+			"docs: explain\n\nFirst paragraph.\n\nNot: a footer paragraph because\nthis line isn't a token",
+			&types.ConventionalCommit{Type: "docs", Subject: "explain", Body: "First paragraph.\n\nNot: a footer paragraph because\nthis line isn't a token"},
+		},
+		{"Merge branch 'main' into feature", nil},
+		{"Update README.md", nil},
+		{"feat:missing space", nil},
+		{"feat(): ", nil},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, Parse(tt.message), tt.message)
+	}
+}
+
+func TestGroup(t *testing.T) {
+	commit := func(hash, message string) types.CommitData {
+		return types.CommitData{Hash: hash, Message: message, Conventional: Parse(message)}
+	}
+	commits := []types.CommitData{
+		commit("a", "fix: one"),
+		commit("b", "Update README"),
+		commit("c", "feat!: two"),
+		commit("d", "feat: three"),
+		commit("e", "ci: four"),
+		commit("f", "fix: five"),
+	}
+	require.True(t, Any(commits))
+	assert.False(t, Any(commits[1:2]))
+
+	var got [][]string
+	for _, section := range Group(commits, "feat", "fix") {
+		titles := []string{section.Title}
+		for _, c := range section.Commits {
+			titles = append(titles, c.Hash)
+		}
+		got = append(got, titles)
+	}
+	assert.Equal(t, [][]string{
+		{"Breaking changes", "c"},
+		{"Features", "d"},
+		{"Bug fixes", "a", "f"},
+		{"Other changes", "b", "e"},
+	}, got)
+}
+
+func TestRecommendBump(t *testing.T) {
+	commit := func(message string) types.CommitData {
+		return types.CommitData{Message: message, Conventional: Parse(message)}
+	}
+	tests := []struct {
+		messages []string
+		expected semver.Level
+	}{
+		{[]string{"docs: typo", "Update README"}, semver.None},
+		{[]string{"chore: deps", "perf: cache lookups"}, semver.Patch},
+		{[]string{"fix: crash", "feat(cli): add --json"}, semver.Minor},
+		{[]string{"feat: add SSO", "fix!: reject empty tokens"}, semver.Major},
+		{[]string{"chore: bump config\n\nBREAKING CHANGE: env is now environment"}, semver.Major},
+	}
+	for _, tt := range tests {
+		var commits []types.CommitData
+		for _, message := range tt.messages {
+			commits = append(commits, commit(message))
+		}
+		assert.Equal(t, tt.expected, RecommendBump(commits).Level, "%v", tt.messages)
+	}
+
+	bump := RecommendBump([]types.CommitData{commit("feat: a"), commit("fix: b"), commit("revert: c"), commit("feat!: d")})
+	assert.Len(t, bump.Breaking, 1)
+	assert.Len(t, bump.Features, 1)
+	assert.Len(t, bump.Fixes, 2)
+}
@@ -0,0 +1,82 @@
+package conventional
+
+import "github.com/frfahim/gitstory/internal/types"
+
+// Section types that aren't commit types
+const (
+	Breaking = "breaking"
+	Other    = "other"
+)
+
+// Titles maps commit types, and the Breaking and Other sections, to headings.
+// Types are listed in TypeOrder.
+var Titles = map[string]string{
+	Breaking:   "Breaking changes",
+	"feat":     "Features",
+	"fix":      "Bug fixes",
+	"perf":     "Performance",
+	"refactor": "Refactoring",
+	"revert":   "Reverts",
+	"docs":     "Documentation",
+	"test":     "Tests",
+	"build":    "Build",
+	"ci":       "CI",
+	"style":    "Style",
+	"chore":    "Chores",
+	Other:      "Other changes",
+}
+
+// TypeOrder is the order of the standard commit types, most relevant first
+var TypeOrder = []string{"feat", "fix", "perf", "refactor", "revert", "docs", "test", "build", "ci", "style", "chore"}
+
+// Section is a titled group of commits
+type Section struct {
+	Type    string
+	Title   string
+	Commits []types.CommitData
+}
+
+// Group sorts commits into sections: breaking changes first, then one section
+// for each of sectionTypes in that order, then Other for every other commit,
+// including those that don't follow Conventional Commits. Commits keep their
+// order within a section and empty sections are left out.
+func Group(commits []types.CommitData, sectionTypes ...string) []Section {
+	byType := map[string][]types.CommitData{}
+	for _, commit := range commits {
+		section := sectionType(commit.Conventional, sectionTypes)
+		byType[section] = append(byType[section], commit)
+	}
+
+	var sections []Section
+	for _, sectionType := range append(append([]string{Breaking}, sectionTypes...), Other) {
+		if len(byType[sectionType]) > 0 {
+			sections = append(sections, Section{Type: sectionType, Title: Titles[sectionType], Commits: byType[sectionType]})
+		}
+	}
+	return sections
+}
+
+func sectionType(commit *types.ConventionalCommit, sectionTypes []string) string {
+	if commit == nil {
+		return Other
+	}
+	if commit.Breaking {
+		return Breaking
+	}
+	for _, sectionType := range sectionTypes {
+		if commit.Type == sectionType {
+			return sectionType
+		}
+	}
+	return Other
+}
+
+// Any reports whether at least one commit follows Conventional Commits
+func Any(commits []types.CommitData) bool {
+	for _, commit := range commits {
+		if commit.Conventional != nil {
+			return true
+		}
+	}
+	return false
+}
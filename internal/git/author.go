@@ -0,0 +1,62 @@
+package git
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// identityMatcher matches "Name <email>" identities against --author/--committer patterns
+type identityMatcher []*regexp.Regexp
+
+// compileIdentityPatterns compiles patterns as case-insensitive regular
+// expressions. A pattern that isn't valid regex is matched as a plain substring.
+func compileIdentityPatterns(patterns []string) identityMatcher {
+	var matcher identityMatcher
+	for _, pattern := range patterns {
+		if pattern == "" {
+			continue
+		}
+		re, err := regexp.Compile("(?i)" + pattern)
+		if err != nil {
+			re = regexp.MustCompile("(?i)" + regexp.QuoteMeta(pattern))
+		}
+		matcher = append(matcher, re)
+	}
+	return matcher
+}
+
+// matches reports whether the signature matches any pattern; an empty matcher matches everything
+func (m identityMatcher) matches(sig object.Signature) bool {
+	if len(m) == 0 {
+		return true
+	}
+	identity := fmt.Sprintf("%s <%s>", sig.Name, sig.Email)
+	for _, re := range m {
+		if re.MatchString(identity) {
+			return true
+		}
+	}
+	return false
+}
+
+// UserEmail returns user.email from the repository, global and system git config
+func (r *Repository) UserEmail() (string, error) {
+	cfg, err := r.repo.ConfigScoped(config.SystemScope)
+	if err != nil {
+		return "", fmt.Errorf("failed to read git config: %w", err)
+	}
+	email := strings.TrimSpace(cfg.User.Email)
+	if email == "" {
+		return "", fmt.Errorf("user.email is not set in git config")
+	}
+	return email, nil
+}
+
+// EmailPattern returns an author pattern matching exactly the given email
+func EmailPattern(email string) string {
+	return "<" + regexp.QuoteMeta(email) + ">"
+}
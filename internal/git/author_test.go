@@ -0,0 +1,65 @@
+package git
+
+import (
+	"testing"
+	"time"
+
+	"github.com/frfahim/gitstory/internal/testutil"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupMultiAuthorRepo(t *testing.T) (*Repository, *testutil.TestRepo) {
+	testRepo := testutil.CreateTestRepo(t)
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	authors := []object.Signature{
+		{Name: "Alice Smith", Email: "alice@example.com"},
+		{Name: "Bob Jones", Email: "bob@corp.example"},
+		{Name: "Alice Smith", Email: "alice@example.com"},
+	}
+	for i, author := range authors {
+		author.When = start.Add(time.Duration(i) * time.Hour)
+		testRepo.AddCommitAs(t, "file.txt", author.Name+string(rune('a'+i)), "commit by "+author.Name, &author)
+	}
+
+	repo, err := OpenRepository(testRepo.Dir)
+	require.NoError(t, err)
+	return repo, testRepo
+}
+
+func TestListCommitsWithOptions_Authors(t *testing.T) {
+	repo, testRepo := setupMultiAuthorRepo(t)
+	defer testRepo.Cleanup()
+
+	tests := []struct {
+		patterns []string
+		expected int
+	}{
+		{[]string{"alice"}, 2},             // case-insensitive substring
+		{[]string{"@corp\\.example>$"}, 1}, // regex on email
+		{[]string{"alice", "bob"}, 3},      // repeatable, OR'd
+		{[]string{"carol"}, 0},             // no match
+		{[]string{"Bob ("}, 0},             // invalid regex is matched literally
+		{[]string{EmailPattern("bob@corp.example")}, 1},
+	}
+	for _, tt := range tests {
+		commits, err := repo.ListCommitsWithOptions(ListOptions{Authors: tt.patterns})
+		require.NoError(t, err)
+		assert.Len(t, commits, tt.expected, "patterns %v", tt.patterns)
+	}
+}
+
+func TestUserEmail(t *testing.T) {
+	repo, testRepo := setupTestRepo(t)
+	defer testRepo.Cleanup()
+
+	cfg, err := testRepo.Repo.Config()
+	require.NoError(t, err)
+	cfg.User.Email = "me@example.com"
+	require.NoError(t, testRepo.Repo.SetConfig(cfg))
+
+	email, err := repo.UserEmail()
+	require.NoError(t, err)
+	assert.Equal(t, "me@example.com", email)
+}
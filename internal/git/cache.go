@@ -0,0 +1,100 @@
+package git
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/storage/filesystem"
+)
+
+// cacheVersion is bumped whenever the extracted CommitDiffDetails change
+// shape or meaning, so stale entries are never read back
+const cacheVersion = 2
+
+// diffCache stores the extracted changes of commits under
+// .git/gitstory/cache/<options>/<commit>.json. Commits never change, so
+// entries stay valid as long as the extraction options match. Content
+// exclusions are applied after reading, so they aren't part of the key.
+// Errors are ignored: a cache that can't be read or written just misses.
+type diffCache struct {
+	dir string
+}
+
+// newDiffCache returns the cache for these options, or nil when the
+// repository has no .git directory to keep it in
+func (r *Repository) newDiffCache(opts DiffOptions) *diffCache {
+	storage, ok := r.repo.Storer.(*filesystem.Storage)
+	if !ok {
+		return nil
+	}
+
+	merges := opts.Merges
+	if merges == "" {
+		merges = MergeFirstParent
+	}
+	key, err := json.Marshal(struct {
+		Version     int
+		IncludeDiff bool
+		Merges      MergeStrategy
+		Paths       []string
+		MaxFileSize int64
+	}{cacheVersion, opts.IncludeDiff, merges, opts.Paths, opts.MaxFileSize})
+	if err != nil {
+		return nil
+	}
+	sum := sha256.Sum256(key)
+	return &diffCache{dir: filepath.Join(storage.Filesystem().Root(), "gitstory", "cache", hex.EncodeToString(sum[:8]))}
+}
+
+func (c *diffCache) path(hash plumbing.Hash) string {
+	return filepath.Join(c.dir, hash.String()+".json")
+}
+
+// get returns the cached changes of a commit
+func (c *diffCache) get(hash plumbing.Hash) (CommitDiffDetails, bool) {
+	var details CommitDiffDetails
+	if c == nil {
+		return details, false
+	}
+	data, err := os.ReadFile(c.path(hash))
+	if err != nil {
+		return details, false
+	}
+	if err := json.Unmarshal(data, &details); err != nil {
+		return details, false
+	}
+	return details, true
+}
+
+// put stores the changes of a commit, writing through a temporary file so a
+// concurrent run never reads a partial entry
+func (c *diffCache) put(hash plumbing.Hash, details CommitDiffDetails) {
+	if c == nil {
+		return
+	}
+	data, err := json.Marshal(details)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(c.dir, 0755); err != nil {
+		return
+	}
+	tmp, err := os.CreateTemp(c.dir, hash.String()+".*.tmp")
+	if err != nil {
+		return
+	}
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), c.path(hash))
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+	}
+}
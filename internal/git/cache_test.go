@@ -0,0 +1,86 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListCommitSummarize_ParallelKeepsOrder(t *testing.T) {
+	repo, testRepo := setupEmptyTestRepo(t)
+	defer testRepo.Cleanup()
+	for i := 0; i < 12; i++ {
+		testRepo.AddCommit(t, fmt.Sprintf("file%d.go", i), fmt.Sprintf("package p\n// %d\n", i), fmt.Sprintf("commit %d", i))
+	}
+
+	commits, err := repo.ListCommits(0)
+	require.NoError(t, err)
+	serial, err := repo.ListCommitSummarize(commits, DiffOptions{IncludeDiff: true, Workers: 1, NoCache: true})
+	require.NoError(t, err)
+	parallel, err := repo.ListCommitSummarize(commits, DiffOptions{IncludeDiff: true, Workers: 4, NoCache: true})
+	require.NoError(t, err)
+
+	require.Len(t, parallel, 12)
+	assert.Equal(t, serial, parallel)
+	assert.Equal(t, "commit 11", parallel[0].Message)
+	assert.Equal(t, "file11.go", parallel[0].Files[0].Path)
+}
+
+func TestListCommitSummarize_Cache(t *testing.T) {
+	repo, testRepo := setupEmptyTestRepo(t)
+	defer testRepo.Cleanup()
+	testRepo.AddCommit(t, "main.go", "package main\n", "main")
+	testRepo.AddCommit(t, "go.sum", "example.com/a v1.0.0 h1:abc=\n", "deps")
+
+	commits, err := repo.ListCommits(0)
+	require.NoError(t, err)
+	opts := DiffOptions{IncludeDiff: true}
+	first, err := repo.ListCommitSummarize(commits, opts)
+	require.NoError(t, err)
+
+	cache := repo.newDiffCache(opts)
+	require.NotNil(t, cache)
+	assert.FileExists(t, cache.path(commits[0].Hash))
+	assert.Equal(t, filepath.Join(testRepo.Dir, ".git", "gitstory", "cache"), filepath.Dir(cache.dir))
+
+	// A cached entry is served as is, with content exclusions applied afterwards
+	details, ok := cache.get(commits[1].Hash)
+	require.True(t, ok)
+	details.Files[0].Content = "ADDITIONS:\n+from the cache"
+	cache.put(commits[1].Hash, details)
+
+	second, err := repo.ListCommitSummarize(commits, opts)
+	require.NoError(t, err)
+	assert.Equal(t, "ADDITIONS:\n+from the cache", second[1].Files[0].Content)
+	assert.Equal(t, first[0], second[0])
+	assert.Equal(t, ExcludedLockfile, second[0].Files[0].Excluded)
+	assert.Empty(t, second[0].Files[0].Content)
+
+	uncached, err := repo.ListCommitSummarize(commits, DiffOptions{IncludeDiff: true, NoCache: true})
+	require.NoError(t, err)
+	assert.Equal(t, first, uncached)
+
+	// Different extraction options use a separate cache
+	assert.NotEqual(t, cache.dir, repo.newDiffCache(DiffOptions{IncludeDiff: true, MaxFileSize: 10}).dir)
+}
+
+func TestDiffCache_IgnoresCorruptEntries(t *testing.T) {
+	repo, testRepo := setupTestRepo(t)
+	defer testRepo.Cleanup()
+
+	commits, err := repo.ListCommits(1)
+	require.NoError(t, err)
+	cache := repo.newDiffCache(DiffOptions{})
+	require.NoError(t, os.MkdirAll(cache.dir, 0755))
+	require.NoError(t, os.WriteFile(cache.path(commits[0].Hash), []byte("{not json"), 0644))
+
+	_, ok := cache.get(commits[0].Hash)
+	assert.False(t, ok)
+	summaries, err := repo.ListCommitSummarize(commits, DiffOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "config.yaml", summaries[0].Files[0].Path)
+}
@@ -2,8 +2,11 @@ package git
 
 import (
 	"fmt"
+	"runtime"
+	"sync"
 	"time"
 
+	"github.com/frfahim/gitstory/internal/conventional"
 	"github.com/frfahim/gitstory/internal/types"
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing"
@@ -11,81 +14,303 @@ import (
 	"github.com/go-git/go-git/v5/plumbing/storer"
 )
 
+// ListOptions controls which commits the listing functions return
+type ListOptions struct {
+	// Limit caps the number of commits returned; 0 means no limit
+	Limit int
+	// Since and Until bound the commit date (inclusive); zero values are ignored
+	Since time.Time
+	Until time.Time
+	// UseAuthorDate filters Since/Until on the author date instead of the committer date
+	UseAuthorDate bool
+	// SkipMerges leaves out commits with more than one parent
+	SkipMerges bool
+	// Authors and Committers keep commits whose "Name <email>" matches any
+	// pattern (case-insensitive regex, or substring if not valid regex)
+	Authors    []string
+	Committers []string
+	// Paths keeps commits touching at least one path selected by these
+	// pathspec entries (see ParsePathspec)
+	Paths []string
+}
+
+// HasDateRange reports whether a Since or Until bound is set
+func (o ListOptions) HasDateRange() bool {
+	return !o.Since.IsZero() || !o.Until.IsZero()
+}
+
+// commitFilter is ListOptions with its patterns compiled
+type commitFilter struct {
+	ListOptions
+	authors    identityMatcher
+	committers identityMatcher
+	paths      *Pathspec
+}
+
+func (o ListOptions) compile() (commitFilter, error) {
+	paths, err := ParsePathspec(o.Paths)
+	if err != nil {
+		return commitFilter{}, err
+	}
+	return commitFilter{
+		ListOptions: o,
+		authors:     compileIdentityPatterns(o.Authors),
+		committers:  compileIdentityPatterns(o.Committers),
+		paths:       paths,
+	}, nil
+}
+
+// matches reports whether a commit passes the filters
+func (o commitFilter) matches(c *object.Commit) bool {
+	if o.SkipMerges && c.NumParents() > 1 {
+		return false
+	}
+	if !o.authors.matches(c.Author) || !o.committers.matches(c.Committer) {
+		return false
+	}
+	when := c.Committer.When
+	if o.UseAuthorDate {
+		when = c.Author.When
+	}
+	if !o.Since.IsZero() && when.Before(o.Since) {
+		return false
+	}
+	if !o.Until.IsZero() && when.After(o.Until) {
+		return false
+	}
+	return true
+}
+
+// touchesPaths reports whether a commit changes any path selected by ps,
+// compared to its first parent (or the empty tree for a root commit)
+func (r *Repository) touchesPaths(c *object.Commit, ps *Pathspec) (bool, error) {
+	tree, err := c.Tree()
+	if err != nil {
+		return false, fmt.Errorf("failed to get commit (%s) tree: %w", c.Hash, err)
+	}
+	parentTree, err := r.getParentTree(c, 0)
+	if err != nil {
+		return false, err
+	}
+	changes, err := object.DiffTree(parentTree, tree)
+	if err != nil {
+		return false, fmt.Errorf("failed to get commit diff: %w", err)
+	}
+	for _, change := range changes {
+		for _, name := range []string{change.From.Name, change.To.Name} {
+			if name != "" && ps.Matches(name) {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
 // ListCommits returns the latest N commits from the repository
 func (r *Repository) ListCommits(n int) ([]*object.Commit, error) {
+	return r.ListCommitsWithOptions(ListOptions{Limit: n})
+}
+
+// ListCommitsWithOptions returns commits reachable from HEAD that match opts
+func (r *Repository) ListCommitsWithOptions(opts ListOptions) ([]*object.Commit, error) {
 	ref, err := r.repo.Head()
 	if err != nil {
 		return nil, err
 	}
-	iter, err := r.repo.Log(&git.LogOptions{From: ref.Hash()})
+	iter, err := r.repo.Log(&git.LogOptions{From: ref.Hash(), Order: git.LogOrderCommitterTime})
 	if err != nil {
 		return nil, err
 	}
+	return r.collectCommits(iter, plumbing.ZeroHash, opts)
+}
+
+// collectCommits drains a newest-first commit iterator, stopping at `stopAt`
+// (if set) or once opts.Limit matching commits have been collected
+func (r *Repository) collectCommits(iter object.CommitIter, stopAt plumbing.Hash, opts ListOptions) ([]*object.Commit, error) {
 	defer iter.Close()
+
+	filter, err := opts.compile()
+	if err != nil {
+		return nil, err
+	}
 	var commits []*object.Commit
-	count := 0
 	err = iter.ForEach(func(c *object.Commit) error {
-		if count >= n {
+		if !stopAt.IsZero() && c.Hash == stopAt {
+			return storer.ErrStop
+		}
+		if opts.Limit > 0 && len(commits) >= opts.Limit {
+			return storer.ErrStop
+		}
+		// Commits come newest-first by committer time, so nothing older can match
+		if !opts.UseAuthorDate && !opts.Since.IsZero() && c.Committer.When.Before(opts.Since) {
 			return storer.ErrStop
 		}
+		if !filter.matches(c) {
+			return nil
+		}
+		if !filter.paths.IsEmpty() {
+			touched, err := r.touchesPaths(c, filter.paths)
+			if err != nil {
+				return err
+			}
+			if !touched {
+				return nil
+			}
+		}
 		commits = append(commits, c)
-		count++
 		return nil
 	})
 	return commits, err
 }
 
+// NewCommitData returns a commit's metadata, with its message parsed as a
+// Conventional Commit, but without its file changes
+func NewCommitData(commit *object.Commit) types.CommitData {
+	return types.CommitData{
+		Hash:    commit.Hash.String()[:7],
+		Author:  commit.Author.Name,
+		Date:    commit.Author.When.Format(time.RFC3339),
+		Message: commit.Message,
+		// Parsed here rather than cached: it only depends on the message
+		Conventional: conventional.Parse(commit.Message),
+	}
+}
+
 // ListCommitSummarize returns summary info for last N commits
-func (repo *Repository) ListCommitSummarize(commits []*object.Commit) ([]types.CommitData, error) {
+func (repo *Repository) ListCommitSummarize(commits []*object.Commit, opts DiffOptions) ([]types.CommitData, error) {
+	filter, err := repo.NewContentFilter(opts.ContentExcludes, opts.AllContent)
+	if err != nil {
+		return nil, err
+	}
+	opts.contentFilter = filter
+
+	var cache *diffCache
+	if !opts.NoCache {
+		cache = repo.newDiffCache(opts)
+	}
+
 	var summarize []types.CommitData
+	var pending []int
+	var pendingCommits []*object.Commit
 	for _, commit := range commits {
-		commitSummary := types.CommitData{
-			Hash:    commit.Hash.String()[:7],
-			Author:  commit.Author.Name,
-			Date:    commit.Author.When.Format(time.RFC3339),
-			Message: commit.Message,
+		if commit.NumParents() > 1 && opts.Merges == MergeSkip {
+			continue
+		}
+		commitSummary := NewCommitData(commit)
+		if details, ok := cache.get(commit.Hash); ok {
+			commitSummary.Files = details.Files
+			commitSummary.Stats = details.Stats
+		} else {
+			pending = append(pending, len(summarize))
+			pendingCommits = append(pendingCommits, commit)
 		}
-		details, _ := repo.GetCommitDiffDetails(commit, true)
-		commitSummary.Files = details.Files
-		commitSummary.Stats = details.Stats
 		summarize = append(summarize, commitSummary)
 	}
+
+	extracted, err := repo.extractAll(pendingCommits, opts)
+	if err != nil {
+		return nil, err
+	}
+	for i, details := range extracted {
+		cache.put(pendingCommits[i].Hash, details)
+		summarize[pending[i]].Files = details.Files
+		summarize[pending[i]].Stats = details.Stats
+	}
+
+	for i := range summarize {
+		filter.apply(summarize[i].Files)
+	}
 	return summarize, nil
 }
 
+// maxWorkers caps the default number of parallel diff workers
+const maxWorkers = 8
+
+// extractAll diffs commits on a bounded pool of workers and returns their
+// changes in the order of commits, without content exclusions applied.
+// go-git repositories aren't safe for concurrent reads (pack indexes fill
+// caches lazily), so each worker opens its own copy of the repository.
+func (repo *Repository) extractAll(commits []*object.Commit, opts DiffOptions) ([]CommitDiffDetails, error) {
+	results := make([]CommitDiffDetails, len(commits))
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = min(runtime.NumCPU(), maxWorkers)
+	}
+	workers = min(workers, len(commits))
+
+	if workers <= 1 || repo.path == "" {
+		for i, commit := range commits {
+			files, stats, err := repo.extractFileChanges(commit, opts)
+			if err != nil {
+				return nil, err
+			}
+			results[i] = CommitDiffDetails{Files: files, Stats: stats}
+		}
+		return results, nil
+	}
+
+	errs := make([]error, len(commits))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			worker, openErr := OpenRepository(repo.path)
+			for i := range jobs {
+				if openErr != nil {
+					errs[i] = fmt.Errorf("failed to open repository for diff worker: %w", openErr)
+					continue
+				}
+				commit, err := worker.repo.CommitObject(commits[i].Hash)
+				if err != nil {
+					errs[i] = fmt.Errorf("failed to read commit %s: %w", commits[i].Hash, err)
+					continue
+				}
+				files, stats, err := worker.extractFileChanges(commit, opts)
+				results[i], errs[i] = CommitDiffDetails{Files: files, Stats: stats}, err
+			}
+		}()
+	}
+	for i := range commits {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return results, nil
+}
+
 // ListUniqueCommits returns commits unique to the current branch (not in baseBranch)
 func (r *Repository) ListUniqueCommits(baseBranch string, n int) ([]*object.Commit, error) {
+	return r.ListUniqueCommitsWithOptions(baseBranch, ListOptions{Limit: n})
+}
+
+// ListUniqueCommitsWithOptions returns commits unique to the current branch
+// (not in baseBranch) that match opts. The base can be any revision: a local
+// or remote-tracking branch such as origin/main, a tag or a commit SHA.
+func (r *Repository) ListUniqueCommitsWithOptions(baseBranch string, opts ListOptions) ([]*object.Commit, error) {
 	ref, err := r.repo.Head()
 	if err != nil {
 		return nil, err
 	}
-	baseRef, err := r.repo.Reference(plumbing.NewBranchReferenceName(baseBranch), true)
+	baseHash, err := r.ResolveRevision(baseBranch)
 	if err != nil {
-		return nil, fmt.Errorf("base branch '%s' not found: %w", baseBranch, err)
+		return nil, fmt.Errorf("base '%s' not found: %w", baseBranch, err)
 	}
-	mergeBase, err := FindMergeBase(r.repo, ref.Hash(), baseRef.Hash())
+	mergeBase, err := FindMergeBase(r.repo, ref.Hash(), baseHash)
 	if err != nil {
 		return nil, fmt.Errorf("failed to find merge-base: %w", err)
 	}
 
-	iter, err := r.repo.Log(&git.LogOptions{From: ref.Hash()})
+	iter, err := r.repo.Log(&git.LogOptions{From: ref.Hash(), Order: git.LogOrderCommitterTime})
 	if err != nil {
 		return nil, err
 	}
-	defer iter.Close()
-
-	var commits []*object.Commit
-	count := 0
-	err = iter.ForEach(func(c *object.Commit) error {
-		if c.Hash == mergeBase {
-			return storer.ErrStop
-		}
-		if count >= n {
-			return storer.ErrStop
-		}
-		commits = append(commits, c)
-		count++
-		return nil
-	})
-	return commits, err
+	return r.collectCommits(iter, mergeBase, opts)
 }
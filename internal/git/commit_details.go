@@ -1,11 +1,14 @@
 package git
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"path/filepath"
 	"strings"
 
 	"github.com/frfahim/gitstory/internal/types"
+	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/object"
 )
 
@@ -14,8 +17,74 @@ type CommitDiffDetails struct {
 	Stats types.CommitStats
 }
 
+// MergeStrategy controls how merge commits are diffed
+type MergeStrategy string
+
+const (
+	// MergeFirstParent diffs a merge against its first parent, i.e. everything the merge brought in
+	MergeFirstParent MergeStrategy = "first-parent"
+	// MergeCombined keeps only files that differ from every parent (conflict resolutions, evil merges)
+	MergeCombined MergeStrategy = "combined"
+	// MergeSkip leaves merge commits out entirely
+	MergeSkip MergeStrategy = "skip"
+)
+
+// ParseMergeStrategy validates a --merges value; empty means first-parent
+func ParseMergeStrategy(value string) (MergeStrategy, error) {
+	switch MergeStrategy(value) {
+	case "", MergeFirstParent:
+		return MergeFirstParent, nil
+	case MergeCombined, MergeSkip:
+		return MergeStrategy(value), nil
+	}
+	return "", fmt.Errorf("unsupported merge strategy '%s'. Supported: first-parent, combined, skip", value)
+}
+
+// DefaultMaxFileSize is the largest blob that is diffed by default
+const DefaultMaxFileSize int64 = 1 << 20
+
+// DiffOptions controls how file changes are extracted from a commit
+type DiffOptions struct {
+	// IncludeDiff keeps each file's diff in Content; without it only the
+	// changed files and their line counts are extracted
+	IncludeDiff bool
+	Merges      MergeStrategy
+	// Paths limits extracted files to those selected by these pathspec entries
+	Paths []string
+	// ContentExcludes are extra pathspec patterns whose content is left out;
+	// ":(exclude)pattern" keeps content a default pattern would drop
+	ContentExcludes []string
+	// AllContent turns off the built-in lockfile/vendored/generated exclusions
+	AllContent bool
+	// MaxFileSize is the blob size in bytes above which a file isn't diffed;
+	// 0 means DefaultMaxFileSize, a negative value means no limit
+	MaxFileSize int64
+
+	// Workers is the number of commits ListCommitSummarize diffs in parallel;
+	// 0 picks one per CPU, up to maxWorkers
+	Workers int
+	// NoCache makes ListCommitSummarize recompute every diff instead of
+	// reading and writing the on-disk cache
+	NoCache bool
+
+	// contentFilter is built once per ListCommitSummarize rather than per commit
+	contentFilter *ContentFilter
+}
+
 func (r *Repository) GetCommitDiffDetails(commit *object.Commit, includeDiff bool) (CommitDiffDetails, error) {
-	commitDiff, stats, err := r.extractFileChanges(commit, includeDiff)
+	return r.GetCommitDiffDetailsWithOptions(commit, DiffOptions{IncludeDiff: includeDiff})
+}
+
+func (r *Repository) GetCommitDiffDetailsWithOptions(commit *object.Commit, opts DiffOptions) (CommitDiffDetails, error) {
+	if opts.contentFilter == nil {
+		filter, err := r.NewContentFilter(opts.ContentExcludes, opts.AllContent)
+		if err != nil {
+			return CommitDiffDetails{}, err
+		}
+		opts.contentFilter = filter
+	}
+	commitDiff, stats, err := r.extractFileChanges(commit, opts)
+	opts.contentFilter.apply(commitDiff)
 	return CommitDiffDetails{
 		Files: commitDiff,
 		Stats: stats,
@@ -23,37 +92,80 @@ func (r *Repository) GetCommitDiffDetails(commit *object.Commit, includeDiff boo
 
 }
 
-func (r *Repository) extractFileChanges(commit *object.Commit, includeDiff bool) ([]types.FileChange, types.CommitStats, error) {
-	var files []types.FileChange
-	var stats types.CommitStats
-	languageCount := make(map[string]int)
+func (r *Repository) extractFileChanges(commit *object.Commit, opts DiffOptions) ([]types.FileChange, types.CommitStats, error) {
+	if commit.NumParents() > 1 && opts.Merges == MergeSkip {
+		return nil, types.CommitStats{}, nil
+	}
 
 	// Get the current commit tree object
 	currentTree, err := commit.Tree()
 	if err != nil {
-		return files, stats, fmt.Errorf("failed to get current commit (%s) tree: %w", commit.Hash, err)
+		return nil, types.CommitStats{}, fmt.Errorf("failed to get current commit (%s) tree: %w", commit.Hash, err)
 	}
-	// Get the parent commit and it's tree
-	parentCommit, err := r.getParentCommit(commit)
+	// Get the first parent's tree; root commits are diffed against the empty tree
+	parentTree, err := r.getParentTree(commit, 0)
 	if err != nil {
-		// TODO: handle when parent commit is empty
-		return nil, stats, fmt.Errorf("failed to get parent commit from commit(%s): %w", commit.Hash, err)
+		return nil, types.CommitStats{}, err
 	}
-	parentTree, err := parentCommit.Tree()
+
+	fileChanges, err := treeChanges(parentTree, currentTree)
 	if err != nil {
-		return files, stats, fmt.Errorf("failed to get parent commit (%s) tree: %w", parentCommit.Hash, err)
+		return nil, types.CommitStats{}, err
 	}
 
-	// Get the file changes between the parent and current commit
-	fileChanges, err := parentTree.Diff(currentTree)
+	if commit.NumParents() > 1 && opts.Merges == MergeCombined {
+		fileChanges, err = r.combinedChanges(commit, currentTree, fileChanges)
+		if err != nil {
+			return nil, types.CommitStats{}, err
+		}
+	}
+	return r.summarizeChanges(fileChanges, opts)
+}
+
+// treeChanges returns the file changes between two trees, pairing similar
+// deletes and inserts into renames and exact copies of old files into copies.
+// A nil tree is the empty tree.
+func treeChanges(from, to *object.Tree) (object.Changes, error) {
+	changes, err := object.DiffTreeWithOptions(context.Background(), from, to, renameOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get commit diff: %w", err)
+	}
+	return detectCopies(from, changes)
+}
+
+// summarizeChanges turns the changes inside the pathspec of opts into file
+// changes and their statistics
+func (r *Repository) summarizeChanges(fileChanges object.Changes, opts DiffOptions) ([]types.FileChange, types.CommitStats, error) {
+	var files []types.FileChange
+	var stats types.CommitStats
+	languageCount := make(map[string]int)
+
+	// Keep only the files inside the requested pathspec
+	paths, err := ParsePathspec(opts.Paths)
 	if err != nil {
-		return files, stats, fmt.Errorf("failed to get commit diff: %w", err)
+		return files, stats, err
+	}
+	if !paths.IsEmpty() {
+		var selected object.Changes
+		for _, change := range fileChanges {
+			// A rename is kept when either of its names is selected
+			for _, name := range []string{change.From.Name, change.To.Name} {
+				if name != "" && paths.Matches(name) {
+					selected = append(selected, change)
+					break
+				}
+			}
+		}
+		fileChanges = selected
 	}
 
 	stats.TotalFiles = len(fileChanges)
 	// Collect file change statistics
 	for _, change := range fileChanges {
-		fileChange := r.processFileChange(change)
+		fileChange := r.processFileChange(change, opts.MaxFileSize)
+		if !opts.IncludeDiff {
+			fileChange.Content = ""
+		}
 		files = append(files, fileChange)
 		stats.TotalLines += fileChange.Additions + fileChange.Deletions
 		stats.Additions += fileChange.Additions
@@ -76,9 +188,94 @@ func (r *Repository) extractFileChanges(commit *object.Commit, includeDiff bool)
 	return files, stats, nil
 }
 
-// Process a single file change
-func (r *Repository) processFileChange(change *object.Change) types.FileChange {
-	var path, status string
+// renameOptions pairs files at git's default 50% similarity, comparing at
+// most 1000 delete/insert pairs so huge commits stay fast
+var renameOptions = &object.DiffTreeOptions{
+	DetectRenames: true,
+	RenameScore:   50,
+	RenameLimit:   1000,
+}
+
+// emptyBlob is the hash of an empty file, which is never treated as a copy
+var emptyBlob = plumbing.ComputeHash(plumbing.BlobObject, nil)
+
+// detectCopies turns inserts whose content already exists in the parent tree
+// into copies of that file, so duplicating a file doesn't count as new lines.
+// Like git's --find-copies-harder, any file of the parent is a candidate, but
+// only exact copies are found.
+func detectCopies(parentTree *object.Tree, changes object.Changes) (object.Changes, error) {
+	inserted := map[plumbing.Hash][]*object.Change{}
+	for _, change := range changes {
+		if change.From.Name == "" && change.To.TreeEntry.Hash != emptyBlob {
+			inserted[change.To.TreeEntry.Hash] = append(inserted[change.To.TreeEntry.Hash], change)
+		}
+	}
+	if parentTree == nil || len(inserted) == 0 {
+		return changes, nil
+	}
+
+	walker := object.NewTreeWalker(parentTree, true, nil)
+	defer walker.Close()
+	for len(inserted) > 0 {
+		name, entry, err := walker.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to walk parent tree: %w", err)
+		}
+		if !entry.Mode.IsFile() {
+			continue
+		}
+		for _, change := range inserted[entry.Hash] {
+			change.From = object.ChangeEntry{Name: name, Tree: parentTree, TreeEntry: entry}
+		}
+		delete(inserted, entry.Hash)
+	}
+	return changes, nil
+}
+
+// combinedChanges narrows a merge's first-parent changes to the files that
+// also differ from every other parent, like git's combined diff
+func (r *Repository) combinedChanges(commit *object.Commit, currentTree *object.Tree, firstParent object.Changes) (object.Changes, error) {
+	combined := firstParent
+	for i := 1; i < commit.NumParents(); i++ {
+		parentTree, err := r.getParentTree(commit, i)
+		if err != nil {
+			return nil, err
+		}
+		changes, err := object.DiffTree(parentTree, currentTree)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get commit diff against parent %d: %w", i, err)
+		}
+		changed := make(map[string]struct{}, len(changes))
+		for _, change := range changes {
+			changed[changePath(change)] = struct{}{}
+		}
+
+		var kept object.Changes
+		for _, change := range combined {
+			if _, ok := changed[changePath(change)]; ok {
+				kept = append(kept, change)
+			}
+		}
+		combined = kept
+	}
+	return combined, nil
+}
+
+// changePath returns the path a change applies to
+func changePath(change *object.Change) string {
+	if change.To.Name != "" {
+		return change.To.Name
+	}
+	return change.From.Name
+}
+
+// Process a single file change. Binary files and files larger than maxSize
+// are not diffed; they are recorded with their sizes instead.
+func (r *Repository) processFileChange(change *object.Change, maxSize int64) types.FileChange {
+	var path, oldPath, status string
 	var additionCount, deletionCount int = 0, 0
 
 	action, _ := change.Action()
@@ -88,11 +285,36 @@ func (r *Repository) processFileChange(change *object.Change) types.FileChange {
 	} else {
 		path = change.To.Name
 	}
+	// A moved file's source is gone from the new tree; a copy's is still there
+	if change.From.Name != "" && change.To.Name != "" && change.From.Name != change.To.Name {
+		oldPath = change.From.Name
+		status = types.StatusRenamed
+		if _, err := change.To.Tree.FindEntry(oldPath); err == nil {
+			status = types.StatusCopied
+		}
+	}
+
+	from, to, err := change.Files()
+	if err != nil {
+		return types.FileChange{
+			Path:    path,
+			OldPath: oldPath,
+			Status:  status,
+		}
+	}
+	if fileChange, ok := undiffableFile(from, to, maxSize); ok {
+		fileChange.Path = path
+		fileChange.OldPath = oldPath
+		fileChange.Status = status
+		return fileChange
+	}
+
 	patch, err := change.Patch()
 	if err != nil {
 		return types.FileChange{
-			Path:   path,
-			Status: status,
+			Path:    path,
+			OldPath: oldPath,
+			Status:  status,
 		}
 	}
 	// Get the file statistics from the patch
@@ -103,6 +325,7 @@ func (r *Repository) processFileChange(change *object.Change) types.FileChange {
 
 	fileChange := types.FileChange{
 		Path:      path,
+		OldPath:   oldPath,
 		Status:    status,
 		Additions: additionCount,
 		Deletions: deletionCount,
@@ -112,6 +335,41 @@ func (r *Repository) processFileChange(change *object.Change) types.FileChange {
 	return fileChange
 }
 
+// undiffableFile returns the sizes of a change that shouldn't be diffed: one
+// where either side is binary or larger than maxSize. ok is false otherwise.
+func undiffableFile(from, to *object.File, maxSize int64) (types.FileChange, bool) {
+	if maxSize == 0 {
+		maxSize = DefaultMaxFileSize
+	}
+
+	var fileChange types.FileChange
+	for _, side := range []struct {
+		file *object.File
+		size *int64
+	}{{from, &fileChange.OldSize}, {to, &fileChange.NewSize}} {
+		if side.file == nil {
+			continue
+		}
+		*side.size = side.file.Size
+		if maxSize > 0 && side.file.Size > maxSize {
+			fileChange.Truncated = true
+		}
+	}
+	// Checking the size first avoids reading huge blobs to sniff them
+	if !fileChange.Truncated {
+		for _, file := range []*object.File{from, to} {
+			if file == nil {
+				continue
+			}
+			if binary, err := file.IsBinary(); err == nil && binary {
+				fileChange.Binary = true
+				break
+			}
+		}
+	}
+	return fileChange, fileChange.Binary || fileChange.Truncated
+}
+
 // Extract the changes from a patch String
 func (r *Repository) extractChangesOnly(patch *object.Patch) string {
 	var additions, deletions, result strings.Builder
@@ -141,16 +399,20 @@ func (r *Repository) extractChangesOnly(patch *object.Patch) string {
 	return strings.TrimSpace(result.String())
 }
 
-// Get the parent commit
-func (r *Repository) getParentCommit(commit *object.Commit) (*object.Commit, error) {
+// Get the tree of the i-th parent, or nil (the empty tree) for a root commit
+func (r *Repository) getParentTree(commit *object.Commit, i int) (*object.Tree, error) {
 	if commit.NumParents() == 0 {
-		return nil, fmt.Errorf("commit (%s) has no parent", commit.Hash)
+		return nil, nil
 	}
-	parentCommit, err := commit.Parent(0)
+	parentCommit, err := commit.Parent(i)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get parent commit from commit(%s): %w", commit.Hash, err)
+	}
+	parentTree, err := parentCommit.Tree()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get parent commit (%s): %w", commit.Hash, err)
+		return nil, fmt.Errorf("failed to get parent commit (%s) tree: %w", parentCommit.Hash, err)
 	}
-	return parentCommit, nil
+	return parentTree, nil
 }
 
 func (r *Repository) detectLanguage(filePath string) string {
@@ -0,0 +1,198 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/frfahim/gitstory/internal/testutil"
+	"github.com/frfahim/gitstory/internal/types"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetCommitDiffDetails_RootCommit(t *testing.T) {
+	repo, testRepo := setupEmptyTestRepo(t)
+	defer testRepo.Cleanup()
+	testRepo.AddCommit(t, "README.md", "# Test Project\nhello\n", "Initial commit")
+
+	commits, err := repo.ListCommits(1)
+	require.NoError(t, err)
+	require.Len(t, commits, 1)
+
+	details, err := repo.GetCommitDiffDetails(commits[0], true)
+	require.NoError(t, err)
+	require.Len(t, details.Files, 1)
+	assert.Equal(t, "README.md", details.Files[0].Path)
+	assert.Equal(t, "Insert", details.Files[0].Status)
+	assert.Equal(t, 2, details.Stats.Additions)
+	assert.Contains(t, details.Files[0].Content, "+hello")
+
+	// Without the diff only the file and its line counts are extracted
+	details, err = repo.GetCommitDiffDetails(commits[0], false)
+	require.NoError(t, err)
+	require.Len(t, details.Files, 1)
+	assert.Equal(t, 2, details.Files[0].Additions)
+	assert.Empty(t, details.Files[0].Content)
+}
+
+func TestGetCommitDiffDetails_BinaryAndLargeFiles(t *testing.T) {
+	repo, testRepo := setupEmptyTestRepo(t)
+	defer testRepo.Cleanup()
+	testRepo.AddCommit(t, "logo.png", "\x89PNG\x00\x01", "add logo")
+	testRepo.AddCommit(t, "logo.png", "\x89PNG\x00\x01\x02\x03", "update logo")
+	testRepo.AddCommit(t, "data.csv", strings.Repeat("a,b\n", 100), "add data")
+
+	commits, err := repo.ListCommits(3)
+	require.NoError(t, err)
+	require.Len(t, commits, 3)
+
+	details, err := repo.GetCommitDiffDetailsWithOptions(commits[1], DiffOptions{IncludeDiff: true})
+	require.NoError(t, err)
+	require.Len(t, details.Files, 1)
+	logo := details.Files[0]
+	assert.True(t, logo.Binary)
+	assert.False(t, logo.Truncated)
+	assert.Empty(t, logo.Content)
+	assert.Equal(t, int64(6), logo.OldSize)
+	assert.Equal(t, int64(8), logo.NewSize)
+
+	details, err = repo.GetCommitDiffDetailsWithOptions(commits[0], DiffOptions{IncludeDiff: true, MaxFileSize: 100})
+	require.NoError(t, err)
+	data := details.Files[0]
+	assert.True(t, data.Truncated)
+	assert.Empty(t, data.Content)
+	assert.Zero(t, data.OldSize)
+	assert.Equal(t, int64(400), data.NewSize)
+
+	details, err = repo.GetCommitDiffDetailsWithOptions(commits[0], DiffOptions{IncludeDiff: true})
+	require.NoError(t, err)
+	assert.False(t, details.Files[0].Truncated)
+	assert.Equal(t, 100, details.Files[0].Additions)
+}
+
+func TestGetCommitDiffDetails_RenamesAndCopies(t *testing.T) {
+	repo, testRepo := setupEmptyTestRepo(t)
+	defer testRepo.Cleanup()
+	body := strings.Repeat("func helper() {}\n", 20)
+	testRepo.AddCommit(t, "util.go", "package util\n"+body, "add util")
+	testRepo.MoveFile(t, "util.go", "helpers.go", "move util")
+	testRepo.AddCommit(t, "helpers_copy.go", "package util\n"+body, "copy helpers")
+
+	commits, err := repo.ListCommits(3)
+	require.NoError(t, err)
+	require.Len(t, commits, 3)
+
+	details, err := repo.GetCommitDiffDetails(commits[1], true)
+	require.NoError(t, err)
+	require.Len(t, details.Files, 1)
+	assert.Equal(t, types.FileChange{Path: "helpers.go", OldPath: "util.go", Status: types.StatusRenamed}, details.Files[0])
+	assert.Zero(t, details.Stats.TotalLines, "a pure move isn't a rewrite")
+
+	details, err = repo.GetCommitDiffDetails(commits[0], true)
+	require.NoError(t, err)
+	require.Len(t, details.Files, 1)
+	assert.Equal(t, "helpers_copy.go", details.Files[0].Path)
+	assert.Equal(t, "helpers.go", details.Files[0].OldPath)
+	assert.Equal(t, types.StatusCopied, details.Files[0].Status)
+	assert.Zero(t, details.Stats.Additions)
+}
+
+// createMergeRepo builds master and feature branches and merges them with an
+// extra edit made in the merge commit itself
+func createMergeRepo(t *testing.T) (*Repository, *testutil.TestRepo, *object.Commit) {
+	testRepo := testutil.CreateTestRepo(t)
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	testRepo.AddCommitAt(t, "shared.txt", "base\n", "base", start)
+
+	worktree, err := testRepo.Repo.Worktree()
+	require.NoError(t, err)
+
+	require.NoError(t, worktree.Checkout(&git.CheckoutOptions{
+		Branch: plumbing.NewBranchReferenceName("feature"),
+		Create: true,
+	}))
+	testRepo.AddCommitAt(t, "feature.txt", "feature\n", "feature work", start.Add(time.Hour))
+	feature, err := testRepo.Repo.Head()
+	require.NoError(t, err)
+
+	require.NoError(t, worktree.Checkout(&git.CheckoutOptions{Branch: plumbing.NewBranchReferenceName("master")}))
+	testRepo.AddCommitAt(t, "master.txt", "master\n", "master work", start.Add(2*time.Hour))
+	master, err := testRepo.Repo.Head()
+	require.NoError(t, err)
+
+	// Bring in feature.txt and resolve shared.txt by hand
+	require.NoError(t, os.WriteFile(filepath.Join(testRepo.Dir, "feature.txt"), []byte("feature\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(testRepo.Dir, "shared.txt"), []byte("resolved\n"), 0644))
+	_, err = worktree.Add(".")
+	require.NoError(t, err)
+	sig := &object.Signature{Name: "Test User", Email: "test@example.com", When: start.Add(3 * time.Hour)}
+	mergeHash, err := worktree.Commit("Merge feature", &git.CommitOptions{
+		Author:  sig,
+		Parents: []plumbing.Hash{master.Hash(), feature.Hash()},
+	})
+	require.NoError(t, err)
+
+	repo, err := OpenRepository(testRepo.Dir)
+	require.NoError(t, err)
+	merge, err := testRepo.Repo.CommitObject(mergeHash)
+	require.NoError(t, err)
+	return repo, testRepo, merge
+}
+
+func filePaths(details CommitDiffDetails) []string {
+	var paths []string
+	for _, f := range details.Files {
+		paths = append(paths, f.Path)
+	}
+	return paths
+}
+
+func TestGetCommitDiffDetails_MergeStrategies(t *testing.T) {
+	repo, testRepo, merge := createMergeRepo(t)
+	defer testRepo.Cleanup()
+
+	details, err := repo.GetCommitDiffDetailsWithOptions(merge, DiffOptions{Merges: MergeFirstParent})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"feature.txt", "shared.txt"}, filePaths(details))
+
+	details, err = repo.GetCommitDiffDetailsWithOptions(merge, DiffOptions{Merges: MergeCombined})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"shared.txt"}, filePaths(details))
+	assert.Equal(t, 1, details.Stats.TotalFiles)
+
+	details, err = repo.GetCommitDiffDetailsWithOptions(merge, DiffOptions{Merges: MergeSkip})
+	require.NoError(t, err)
+	assert.Empty(t, details.Files)
+}
+
+func TestListCommitSummarize_SkipMerges(t *testing.T) {
+	repo, testRepo, _ := createMergeRepo(t)
+	defer testRepo.Cleanup()
+
+	commits, err := repo.ListCommitsWithOptions(ListOptions{})
+	require.NoError(t, err)
+	assert.Len(t, commits, 4)
+
+	summaries, err := repo.ListCommitSummarize(commits, DiffOptions{Merges: MergeSkip})
+	require.NoError(t, err)
+	assert.Len(t, summaries, 3)
+
+	commits, err = repo.ListCommitsWithOptions(ListOptions{SkipMerges: true})
+	require.NoError(t, err)
+	assert.Len(t, commits, 3)
+}
+
+func TestParseMergeStrategy(t *testing.T) {
+	strategy, err := ParseMergeStrategy("")
+	require.NoError(t, err)
+	assert.Equal(t, MergeFirstParent, strategy)
+
+	_, err = ParseMergeStrategy("octopus")
+	assert.Error(t, err)
+}
@@ -2,7 +2,11 @@ package git
 
 import (
 	"testing"
+	"time"
 
+	"github.com/frfahim/gitstory/internal/testutil"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -24,3 +28,58 @@ func TestListCommits_WithLimit(t *testing.T) {
 	require.NoError(t, err)
 	assert.Len(t, commits, 1)
 }
+
+func TestListCommitsWithOptions_DateRange(t *testing.T) {
+	testRepo := testutil.CreateTestRepo(t)
+	defer testRepo.Cleanup()
+
+	base := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	testRepo.AddCommitAt(t, "a.txt", "a", "April 30", base.AddDate(0, 0, -1))
+	testRepo.AddCommitAt(t, "b.txt", "b", "May 1", base)
+	testRepo.AddCommitAt(t, "c.txt", "c", "May 3", base.AddDate(0, 0, 2))
+
+	repo, err := OpenRepository(testRepo.Dir)
+	require.NoError(t, err)
+
+	commits, err := repo.ListCommitsWithOptions(ListOptions{
+		Since: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+	})
+	require.NoError(t, err)
+	require.Len(t, commits, 2)
+	assert.Equal(t, "May 3", commits[0].Message)
+	assert.Equal(t, "May 1", commits[1].Message)
+
+	commits, err = repo.ListCommitsWithOptions(ListOptions{
+		Until: time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC),
+		Limit: 1,
+	})
+	require.NoError(t, err)
+	require.Len(t, commits, 1)
+	assert.Equal(t, "May 1", commits[0].Message)
+}
+
+func TestListUniqueCommits_Bases(t *testing.T) {
+	repo, testRepo := setupTestRepo(t)
+	defer testRepo.Cleanup()
+
+	base, err := testRepo.Repo.Head()
+	require.NoError(t, err)
+	addRemoteBranch(t, testRepo, "origin", "main")
+	_, err = testRepo.Repo.CreateTag("v1.0.0", base.Hash(), &git.CreateTagOptions{
+		Message: "release", Tagger: &object.Signature{Name: "Test User", Email: "test@example.com", When: time.Now()},
+	})
+	require.NoError(t, err)
+
+	testRepo.AddCommit(t, "feature.go", "package feature", "Add feature")
+	testRepo.AddCommit(t, "feature_test.go", "package feature", "Test feature")
+
+	for _, baseRev := range []string{"origin/main", "v1.0.0", base.Hash().String(), base.Hash().String()[:7], "HEAD~2"} {
+		commits, err := repo.ListUniqueCommits(baseRev, 10)
+		require.NoError(t, err, baseRev)
+		require.Len(t, commits, 2, baseRev)
+		assert.Equal(t, "Test feature", commits[0].Message)
+	}
+
+	_, err = repo.ListUniqueCommits("origin/nope", 10)
+	assert.ErrorContains(t, err, "base 'origin/nope' not found")
+}
@@ -0,0 +1,115 @@
+package git
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// dateLayouts are the absolute formats accepted by ParseDate, tried in order
+var dateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	dayLayout,
+}
+
+// dayLayout is the date-only layout, which names a whole day
+const dayLayout = "2006-01-02"
+
+// relativeUnits maps the unit of a "<N> <unit> ago" phrase to a date offset
+var relativeUnits = map[string]func(t time.Time, n int) time.Time{
+	"second": func(t time.Time, n int) time.Time { return t.Add(-time.Duration(n) * time.Second) },
+	"minute": func(t time.Time, n int) time.Time { return t.Add(-time.Duration(n) * time.Minute) },
+	"hour":   func(t time.Time, n int) time.Time { return t.Add(-time.Duration(n) * time.Hour) },
+	"day":    func(t time.Time, n int) time.Time { return t.AddDate(0, 0, -n) },
+	"week":   func(t time.Time, n int) time.Time { return t.AddDate(0, 0, -7*n) },
+	"month":  func(t time.Time, n int) time.Time { return t.AddDate(0, -n, 0) },
+	"year":   func(t time.Time, n int) time.Time { return t.AddDate(-n, 0, 0) },
+}
+
+// ParseDate parses a date for --since/--until. It accepts RFC3339, YYYY-MM-DD
+// (with optional time) and relative phrases such as "now", "today",
+// "yesterday", "monday", "last friday", "3 days ago" or "2 weeks ago".
+// Relative phrases are resolved against now, in now's location.
+func ParseDate(value string, now time.Time) (time.Time, error) {
+	t, _, err := parseDate(value, now)
+	return t, err
+}
+
+// ParseUntil parses an inclusive upper bound for --until like ParseDate, except
+// that a value naming a whole day, such as 2024-05-02, "yesterday" or "monday",
+// means the end of that day rather than its start
+func ParseUntil(value string, now time.Time) (time.Time, error) {
+	t, wholeDay, err := parseDate(value, now)
+	if err != nil || !wholeDay {
+		return t, err
+	}
+	return time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location()).Add(-time.Nanosecond), nil
+}
+
+// parseDate implements ParseDate; wholeDay reports whether value names a
+// day rather than a moment, in which case t is the start of that day
+func parseDate(value string, now time.Time) (t time.Time, wholeDay bool, err error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, false, fmt.Errorf("empty date")
+	}
+
+	for _, layout := range dateLayouts {
+		if t, err := time.ParseInLocation(layout, value, now.Location()); err == nil {
+			return t, layout == dayLayout, nil
+		}
+	}
+
+	phrase := strings.Join(strings.Fields(strings.ToLower(value)), " ")
+	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	switch phrase {
+	case "now":
+		return now, false, nil
+	case "today":
+		return startOfDay, true, nil
+	case "yesterday":
+		return startOfDay.AddDate(0, 0, -1), true, nil
+	case "last week":
+		return now.AddDate(0, 0, -7), false, nil
+	case "last month":
+		return now.AddDate(0, -1, 0), false, nil
+	case "last year":
+		return now.AddDate(-1, 0, 0), false, nil
+	}
+
+	// "monday", "last monday": start of the most recent such day (today included)
+	if weekday, ok := parseWeekday(strings.TrimPrefix(phrase, "last ")); ok {
+		daysBack := (int(now.Weekday()) - int(weekday) + 7) % 7
+		return startOfDay.AddDate(0, 0, -daysBack), true, nil
+	}
+
+	// "<N> <unit>(s) ago"
+	fields := strings.Fields(phrase)
+	if len(fields) == 3 && fields[2] == "ago" {
+		n, err := strconv.Atoi(fields[0])
+		if err != nil || n < 0 {
+			return time.Time{}, false, fmt.Errorf("invalid date %q: bad count %q", value, fields[0])
+		}
+		unit := strings.TrimSuffix(fields[1], "s")
+		if offset, ok := relativeUnits[unit]; ok {
+			return offset(now, n), false, nil
+		}
+		return time.Time{}, false, fmt.Errorf("invalid date %q: unknown unit %q", value, fields[1])
+	}
+
+	return time.Time{}, false, fmt.Errorf("invalid date %q: use RFC3339, YYYY-MM-DD or a phrase like \"2 weeks ago\"", value)
+}
+
+func parseWeekday(name string) (time.Weekday, bool) {
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		if strings.ToLower(d.String()) == name {
+			return d, true
+		}
+	}
+	return 0, false
+}
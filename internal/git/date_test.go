@@ -0,0 +1,83 @@
+package git
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDate(t *testing.T) {
+	// Wednesday
+	now := time.Date(2024, 5, 8, 15, 30, 0, 0, time.UTC)
+
+	tests := map[string]time.Time{
+		"2024-05-01T10:00:00Z": time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC),
+		"2024-05-01":           time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+		"now":                  now,
+		"today":                time.Date(2024, 5, 8, 0, 0, 0, 0, time.UTC),
+		"yesterday":            time.Date(2024, 5, 7, 0, 0, 0, 0, time.UTC),
+		"Monday":               time.Date(2024, 5, 6, 0, 0, 0, 0, time.UTC),
+		"last wednesday":       time.Date(2024, 5, 8, 0, 0, 0, 0, time.UTC),
+		"3 days ago":           time.Date(2024, 5, 5, 15, 30, 0, 0, time.UTC),
+		"1 week ago":           time.Date(2024, 5, 1, 15, 30, 0, 0, time.UTC),
+		"2 weeks  ago":         time.Date(2024, 4, 24, 15, 30, 0, 0, time.UTC),
+		"6 hours ago":          time.Date(2024, 5, 8, 9, 30, 0, 0, time.UTC),
+	}
+	for input, expected := range tests {
+		got, err := ParseDate(input, now)
+		require.NoError(t, err, input)
+		assert.True(t, expected.Equal(got), "%q: expected %s, got %s", input, expected, got)
+	}
+}
+
+func TestParseUntil(t *testing.T) {
+	now := time.Date(2024, 5, 8, 15, 30, 0, 0, time.UTC)
+	endOf := func(day int) time.Time { return time.Date(2024, 5, day, 23, 59, 59, 999999999, time.UTC) }
+
+	tests := map[string]time.Time{
+		// A whole day is included
+		"2024-05-02": endOf(2),
+		"yesterday":  endOf(7),
+		"monday":     endOf(6),
+		"today":      endOf(8),
+		// A moment is kept as it is
+		"2024-05-02 12:00": time.Date(2024, 5, 2, 12, 0, 0, 0, time.UTC),
+		"3 days ago":       time.Date(2024, 5, 5, 15, 30, 0, 0, time.UTC),
+	}
+	for input, expected := range tests {
+		got, err := ParseUntil(input, now)
+		require.NoError(t, err, input)
+		assert.True(t, expected.Equal(got), "%q: expected %s, got %s", input, expected, got)
+	}
+
+	_, err := ParseUntil("someday", now)
+	assert.Error(t, err)
+}
+
+func TestParseUntil_IncludesTheDay(t *testing.T) {
+	repo, testRepo := setupEmptyTestRepo(t)
+	defer testRepo.Cleanup()
+	testRepo.AddCommitAt(t, "a.txt", "a", "May 1st", time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC))
+	testRepo.AddCommitAt(t, "b.txt", "b", "May 2nd", time.Date(2024, 5, 2, 18, 45, 0, 0, time.UTC))
+	testRepo.AddCommitAt(t, "c.txt", "c", "May 3rd", time.Date(2024, 5, 3, 8, 0, 0, 0, time.UTC))
+
+	// --since 2024-05-02 --until 2024-05-02 selects that day's commits
+	since, err := ParseDate("2024-05-02", time.Now().In(time.UTC))
+	require.NoError(t, err)
+	until, err := ParseUntil("2024-05-02", time.Now().In(time.UTC))
+	require.NoError(t, err)
+	commits, err := repo.ListCommitsWithOptions(ListOptions{Since: since, Until: until})
+	require.NoError(t, err)
+	require.Len(t, commits, 1)
+	assert.Equal(t, "May 2nd", commits[0].Message)
+}
+
+func TestParseDate_Invalid(t *testing.T) {
+	now := time.Now()
+	for _, input := range []string{"", "someday", "x days ago", "3 fortnights ago"} {
+		_, err := ParseDate(input, now)
+		assert.Error(t, err, input)
+	}
+}
@@ -0,0 +1,183 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/frfahim/gitstory/internal/types"
+	"github.com/go-git/go-git/v5/plumbing/format/gitattributes"
+)
+
+// Reasons a file's content is left out of the prompt
+const (
+	ExcludedLockfile   = "lockfile"
+	ExcludedVendored   = "vendored"
+	ExcludedGenerated  = "generated"
+	ExcludedMinified   = "minified"
+	ExcludedConfigured = "excluded"
+)
+
+// defaultContentExcludes are the pathspec patterns, per reason, of files
+// whose changes are counted in the stats but whose content isn't worth a prompt
+var defaultContentExcludes = []struct {
+	reason   string
+	patterns []string
+}{
+	{ExcludedLockfile, []string{
+		"**/go.sum", "**/go.work.sum", "**/package-lock.json", "**/npm-shrinkwrap.json", "**/yarn.lock",
+		"**/pnpm-lock.yaml", "**/bun.lockb", "**/Cargo.lock", "**/Gemfile.lock", "**/poetry.lock",
+		"**/Pipfile.lock", "**/uv.lock", "**/composer.lock", "**/mix.lock", "**/Podfile.lock",
+		"**/pubspec.lock", "**/flake.lock", "**/packages.lock.json", "**/gradle.lockfile",
+	}},
+	{ExcludedVendored, []string{"**/vendor", "**/node_modules", "**/bower_components", "**/third_party"}},
+	{ExcludedGenerated, []string{
+		"*.pb.go", "*.pb.gw.go", "*_pb2.py", "*_pb2_grpc.py", "*.pb.cc", "*.pb.h",
+		"*_generated.go", "*.gen.go", "**/zz_generated.*.go",
+		"*.g.dart", "*.freezed.dart", "*.designer.cs",
+	}},
+	{ExcludedMinified, []string{"*.min.js", "*.min.css", "*.min.mjs", "*.js.map", "*.css.map"}},
+}
+
+// ContentFilter decides which files keep their code changes in prompts. Files
+// it excludes still appear in the file list and stats, without content.
+type ContentFilter struct {
+	rules []contentRule
+	// keep holds ":!pattern" entries that bring a file's content back
+	keep       *Pathspec
+	attributes gitattributes.Matcher
+}
+
+type contentRule struct {
+	reason string
+	spec   *Pathspec
+}
+
+// NewContentFilter builds the filter for this repository: files marked
+// linguist-generated or linguist-vendored in .gitattributes, the built-in
+// lockfile/vendored/generated/minified patterns (unless noDefaults) and the
+// extra pathspec patterns. An extra ":(exclude)pattern" entry keeps content
+// that would otherwise be excluded.
+func (r *Repository) NewContentFilter(extra []string, noDefaults bool) (*ContentFilter, error) {
+	f := &ContentFilter{}
+
+	var configured, keep []string
+	for _, spec := range extra {
+		pattern, negated, err := parsePathspecMagic(spec)
+		if err != nil {
+			return nil, err
+		}
+		if negated {
+			keep = append(keep, pattern)
+		} else {
+			configured = append(configured, spec)
+		}
+	}
+
+	var err error
+	if f.keep, err = ParsePathspec(keep); err != nil {
+		return nil, err
+	}
+	if len(configured) > 0 {
+		spec, err := ParsePathspec(configured)
+		if err != nil {
+			return nil, err
+		}
+		f.rules = append(f.rules, contentRule{ExcludedConfigured, spec})
+	}
+	if !noDefaults {
+		for _, group := range defaultContentExcludes {
+			spec, err := ParsePathspec(group.patterns)
+			if err != nil {
+				return nil, err
+			}
+			f.rules = append(f.rules, contentRule{group.reason, spec})
+		}
+	}
+
+	if f.attributes, err = r.linguistAttributes(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// Excluded returns why path's content is left out, or "" when it is kept
+func (f *ContentFilter) Excluded(path string) string {
+	if f == nil {
+		return ""
+	}
+
+	// .gitattributes wins in both directions, as it does for GitHub's linguist:
+	// either attribute being set excludes the file, and one explicitly unset
+	// keeps it unless the other is set
+	if f.attributes != nil {
+		attrs, _ := f.attributes.Match(strings.Split(path, "/"), []string{"linguist-generated", "linguist-vendored"})
+		unset := false
+		for _, linguist := range []struct{ name, reason string }{
+			{"linguist-generated", ExcludedGenerated},
+			{"linguist-vendored", ExcludedVendored},
+		} {
+			if attr, ok := attrs[linguist.name]; ok {
+				if attr.IsSet() || (attr.IsValueSet() && attr.Value() == "true") {
+					return linguist.reason
+				}
+				unset = true
+			}
+		}
+		if unset {
+			return ""
+		}
+	}
+
+	if !f.keep.IsEmpty() && f.keep.Matches(path) {
+		return ""
+	}
+	for _, rule := range f.rules {
+		if rule.spec.Matches(path) {
+			return rule.reason
+		}
+	}
+	return ""
+}
+
+// apply strips the content of excluded files. They still count towards the
+// commit stats, but their content stays out of prompts.
+func (f *ContentFilter) apply(files []types.FileChange) {
+	for i := range files {
+		if reason := f.Excluded(files[i].Path); reason != "" {
+			files[i].Content = ""
+			files[i].Excluded = reason
+		}
+	}
+}
+
+// linguistAttributes reads the attributes in the root .gitattributes and
+// .git/info/attributes; it returns nil when there are none
+func (r *Repository) linguistAttributes() (gitattributes.Matcher, error) {
+	worktree, err := r.repo.Worktree()
+	if err != nil {
+		// Bare repositories have no working tree to read attributes from
+		return nil, nil
+	}
+
+	var patterns []gitattributes.MatchAttribute
+	for _, path := range []string{".gitattributes", ".git/info/attributes"} {
+		file, err := worktree.Filesystem.Open(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to open %s: %w", path, err)
+		}
+		attrs, err := gitattributes.ReadAttributes(file, nil, true)
+		file.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+		patterns = append(patterns, attrs...)
+	}
+	if len(patterns) == 0 {
+		return nil, nil
+	}
+	return gitattributes.NewMatcher(patterns), nil
+}
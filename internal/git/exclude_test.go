@@ -0,0 +1,94 @@
+package git
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContentFilterDefaults(t *testing.T) {
+	repo, testRepo := setupEmptyTestRepo(t)
+	defer testRepo.Cleanup()
+
+	filter, err := repo.NewContentFilter(nil, false)
+	require.NoError(t, err)
+
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{"go.sum", ExcludedLockfile},
+		{"web/package-lock.json", ExcludedLockfile},
+		{"Cargo.lock", ExcludedLockfile},
+		{"vendor/github.com/pkg/errors/errors.go", ExcludedVendored},
+		{"web/node_modules/react/index.js", ExcludedVendored},
+		{"api/billing.pb.go", ExcludedGenerated},
+		{"static/app.min.js", ExcludedMinified},
+		{"main.go", ""},
+		{"go.mod", ""},
+		{"docs/vendor.md", ""},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, filter.Excluded(tt.path), tt.path)
+	}
+
+	none, err := repo.NewContentFilter(nil, true)
+	require.NoError(t, err)
+	assert.Empty(t, none.Excluded("go.sum"))
+}
+
+func TestContentFilterConfigured(t *testing.T) {
+	repo, testRepo := setupEmptyTestRepo(t)
+	defer testRepo.Cleanup()
+
+	filter, err := repo.NewContentFilter([]string{"fixtures/", ":!go.sum"}, false)
+	require.NoError(t, err)
+
+	assert.Equal(t, ExcludedConfigured, filter.Excluded("fixtures/big.json"))
+	assert.Empty(t, filter.Excluded("go.sum"), "a :! entry keeps a default-excluded file")
+	assert.Equal(t, ExcludedLockfile, filter.Excluded("yarn.lock"))
+
+	_, err = repo.NewContentFilter([]string{":(icase)README"}, false)
+	assert.Error(t, err)
+}
+
+func TestContentFilterGitattributes(t *testing.T) {
+	repo, testRepo := setupEmptyTestRepo(t)
+	defer testRepo.Cleanup()
+	testRepo.AddCommit(t, ".gitattributes", "schema.graphql linguist-generated\ngo.sum -linguist-generated\nextern/** linguist-vendored=true\nextern/** -linguist-generated\n", "attributes")
+
+	filter, err := repo.NewContentFilter(nil, false)
+	require.NoError(t, err)
+
+	assert.Equal(t, ExcludedGenerated, filter.Excluded("schema.graphql"))
+	assert.Equal(t, ExcludedVendored, filter.Excluded("extern/lib/a.c"), "linguist-vendored wins over -linguist-generated")
+	assert.Empty(t, filter.Excluded("go.sum"), "-linguist-generated overrides the defaults")
+	assert.Empty(t, filter.Excluded("main.go"))
+}
+
+func TestExcludedContentKeepsStats(t *testing.T) {
+	repo, testRepo := setupEmptyTestRepo(t)
+	defer testRepo.Cleanup()
+	testRepo.AddCommit(t, "main.go", "package main\n", "main")
+	testRepo.AddCommit(t, "go.sum", "example.com/a v1.0.0 h1:abc=\nexample.com/b v1.0.0 h1:def=\n", "bump deps")
+
+	commits, err := repo.ListCommits(1)
+	require.NoError(t, err)
+	summaries, err := repo.ListCommitSummarize(commits, DiffOptions{IncludeDiff: true})
+	require.NoError(t, err)
+	require.Len(t, summaries, 1)
+	require.Len(t, summaries[0].Files, 1)
+
+	file := summaries[0].Files[0]
+	assert.Equal(t, ExcludedLockfile, file.Excluded)
+	assert.Empty(t, file.Content)
+	assert.Equal(t, 2, file.Additions)
+	assert.Equal(t, 1, summaries[0].Stats.TotalFiles)
+	assert.Equal(t, 2, summaries[0].Stats.Additions)
+
+	summaries, err = repo.ListCommitSummarize(commits, DiffOptions{IncludeDiff: true, AllContent: true})
+	require.NoError(t, err)
+	assert.Empty(t, summaries[0].Files[0].Excluded)
+	assert.Contains(t, summaries[0].Files[0].Content, "example.com/a")
+}
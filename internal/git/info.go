@@ -2,8 +2,11 @@ package git
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
 	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/object"
 )
@@ -53,27 +56,76 @@ func (r *Repository) GetInfo() (*RepoInfo, error) {
 	return info, nil
 }
 
+// DetectDefaultBranch returns the branch the repository treats as its
+// default: what a remote's HEAD points to (e.g. "origin/main", origin first),
+// then init.defaultBranch, main or master as a local branch or, in checkouts
+// without local branches, as a remote-tracking one. A repository with a
+// single branch uses that one.
 func (r *Repository) DetectDefaultBranch() (string, error) {
-	branches, err := r.repo.Branches()
+	remotes, err := r.remoteNames()
 	if err != nil {
 		return "", err
 	}
+	for _, remote := range remotes {
+		head, err := r.repo.Reference(plumbing.NewRemoteHEADReferenceName(remote), false)
+		if err != nil || head.Type() != plumbing.SymbolicReference {
+			continue
+		}
+		if _, err := r.repo.Reference(head.Target(), true); err == nil {
+			return head.Target().Short(), nil
+		}
+	}
+
+	candidates := []string{"main", "master"}
+	if cfg, err := r.repo.ConfigScoped(config.SystemScope); err == nil && cfg.Init.DefaultBranch != "" {
+		candidates = append([]string{cfg.Init.DefaultBranch}, candidates...)
+	}
+	for _, candidate := range candidates {
+		if _, err := r.repo.Reference(plumbing.NewBranchReferenceName(candidate), true); err == nil {
+			return candidate, nil
+		}
+	}
+	for _, candidate := range candidates {
+		for _, remote := range remotes {
+			if _, err := r.repo.Reference(plumbing.NewRemoteReferenceName(remote, candidate), true); err == nil {
+				return remote + "/" + candidate, nil
+			}
+		}
+	}
+
 	var found []string
+	branches, err := r.repo.Branches()
+	if err != nil {
+		return "", err
+	}
 	_ = branches.ForEach(func(ref *plumbing.Reference) error {
-		name := ref.Name().Short()
-		found = append(found, name)
+		found = append(found, ref.Name().Short())
 		return nil
 	})
-	for _, candidate := range []string{"main", "master"} {
-		for _, name := range found {
-			if name == candidate {
-				return name, nil
-			}
-		}
-	}
-	// fallback: return the first branch, if any
-	if len(found) > 0 {
+	switch len(found) {
+	case 0:
+		return "", fmt.Errorf("no branches found in the repository")
+	case 1:
 		return found[0], nil
 	}
-	return "", fmt.Errorf("no branches found in the repository")
+	return "", fmt.Errorf("no remote HEAD, main or master branch among %s", strings.Join(found, ", "))
+}
+
+// remoteNames lists the configured remotes, origin first
+func (r *Repository) remoteNames() ([]string, error) {
+	remotes, err := r.repo.Remotes()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read remotes: %w", err)
+	}
+	var names []string
+	for _, remote := range remotes {
+		names = append(names, remote.Config().Name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if (names[i] == "origin") != (names[j] == "origin") {
+			return names[i] == "origin"
+		}
+		return names[i] < names[j]
+	})
+	return names, nil
 }
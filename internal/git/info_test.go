@@ -5,6 +5,7 @@ import (
 
 	"github.com/frfahim/gitstory/internal/testutil"
 	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
 	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -85,3 +86,62 @@ func TestDetectDefaultBranch_MultipleBranches(t *testing.T) {
 	assert.NotEmpty(t, defaultBranch)
 	assert.Contains(t, append(branches, "master"), defaultBranch) // master might be the initial branch
 }
+
+// addRemoteBranch creates remote-tracking refs/remotes/<remote>/<branch> at HEAD
+func addRemoteBranch(t *testing.T, testRepo *testutil.TestRepo, remote, branch string) {
+	if _, err := testRepo.Repo.Remote(remote); err != nil {
+		_, err := testRepo.Repo.CreateRemote(&config.RemoteConfig{Name: remote, URLs: []string{"https://example.com/" + remote + ".git"}})
+		require.NoError(t, err)
+	}
+	head, err := testRepo.Repo.Head()
+	require.NoError(t, err)
+	require.NoError(t, testRepo.Repo.Storer.SetReference(plumbing.NewHashReference(plumbing.NewRemoteReferenceName(remote, branch), head.Hash())))
+}
+
+func TestDetectDefaultBranch_RemoteHEAD(t *testing.T) {
+	repo, testRepo := setupTestRepo(t)
+	defer testRepo.Cleanup()
+	addRemoteBranch(t, testRepo, "upstream", "trunk")
+	addRemoteBranch(t, testRepo, "origin", "develop")
+	for remote, branch := range map[string]string{"upstream": "trunk", "origin": "develop"} {
+		require.NoError(t, testRepo.Repo.Storer.SetReference(plumbing.NewSymbolicReference(
+			plumbing.NewRemoteHEADReferenceName(remote), plumbing.NewRemoteReferenceName(remote, branch))))
+	}
+
+	defaultBranch, err := repo.DetectDefaultBranch()
+	require.NoError(t, err)
+	assert.Equal(t, "origin/develop", defaultBranch, "origin's HEAD wins over local master and other remotes")
+}
+
+func TestDetectDefaultBranch_RemoteTrackingOnly(t *testing.T) {
+	repo, testRepo := setupTestRepo(t)
+	defer testRepo.Cleanup()
+	addRemoteBranch(t, testRepo, "origin", "main")
+
+	// A CI checkout: detached HEAD, no local branches
+	head, err := testRepo.Repo.Head()
+	require.NoError(t, err)
+	require.NoError(t, testRepo.Repo.Storer.SetReference(plumbing.NewHashReference(plumbing.HEAD, head.Hash())))
+	require.NoError(t, testRepo.Repo.Storer.RemoveReference(plumbing.Master))
+
+	defaultBranch, err := repo.DetectDefaultBranch()
+	require.NoError(t, err)
+	assert.Equal(t, "origin/main", defaultBranch)
+}
+
+func TestDetectDefaultBranch_Ambiguous(t *testing.T) {
+	repo, testRepo := setupTestRepo(t)
+	defer testRepo.Cleanup()
+
+	worktree, err := testRepo.Repo.Worktree()
+	require.NoError(t, err)
+	require.NoError(t, worktree.Checkout(&git.CheckoutOptions{Branch: plumbing.NewBranchReferenceName("feature"), Create: true}))
+	require.NoError(t, testRepo.Repo.Storer.RemoveReference(plumbing.Master))
+	defaultBranch, err := repo.DetectDefaultBranch()
+	require.NoError(t, err)
+	assert.Equal(t, "feature", defaultBranch, "a single branch is the default")
+
+	require.NoError(t, worktree.Checkout(&git.CheckoutOptions{Branch: plumbing.NewBranchReferenceName("staging"), Create: true}))
+	_, err = repo.DetectDefaultBranch()
+	assert.ErrorContains(t, err, "feature, staging")
+}
@@ -1,39 +1,229 @@
 package git
 
 import (
+	"container/heap"
 	"fmt"
+	"sort"
 
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing"
+	commitgraphfmt "github.com/go-git/go-git/v5/plumbing/format/commitgraph/v2"
+	"github.com/go-git/go-git/v5/plumbing/object/commitgraph"
+	"github.com/go-git/go-git/v5/storage/filesystem"
 )
 
-// FindMergeBase returns the hash of the common ancestor between two commits
+// FindMergeBase returns the best common ancestor of two commits, as
+// `git merge-base` does. When criss-cross merges leave several equally good
+// ancestors, the most recent one is returned.
 func FindMergeBase(repo *git.Repository, hash1, hash2 plumbing.Hash) (plumbing.Hash, error) {
-	seen := map[plumbing.Hash]struct{}{}
-	queue := []plumbing.Hash{hash1}
-	for len(queue) > 0 {
-		h := queue[0]
-		queue = queue[1:]
-		seen[h] = struct{}{}
-		commit, err := repo.CommitObject(h)
-		if err != nil {
-			continue
+	bases, err := FindMergeBases(repo, hash1, hash2)
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+	return bases[0], nil
+}
+
+// FindMergeBases returns every best common ancestor of two commits, newest
+// first, like `git merge-base --all`. A best common ancestor is one that
+// isn't an ancestor of any other common ancestor. The commit-graph file is
+// used when the repository has one.
+func FindMergeBases(repo *git.Repository, hash1, hash2 plumbing.Hash) ([]plumbing.Hash, error) {
+	index, closeIndex := commitNodeIndex(repo)
+	defer closeIndex()
+	return findMergeBases(index, hash1, hash2)
+}
+
+// commitNodeIndex returns a node index backed by the commit-graph file when
+// one exists, falling back to the object store for commits it doesn't cover
+func commitNodeIndex(repo *git.Repository) (commitgraph.CommitNodeIndex, func()) {
+	if storage, ok := repo.Storer.(*filesystem.Storage); ok {
+		if graph, err := commitgraphfmt.OpenChainOrFileIndex(storage.Filesystem()); err == nil {
+			return commitgraph.NewGraphCommitNodeIndex(graph, repo.Storer), func() { graph.Close() }
+		}
+	}
+	return commitgraph.NewObjectCommitNodeIndex(repo.Storer), func() {}
+}
+
+func findMergeBases(index commitgraph.CommitNodeIndex, hash1, hash2 plumbing.Hash) ([]plumbing.Hash, error) {
+	one, err := index.Get(hash1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read commit %s: %w", hash1, err)
+	}
+	two, err := index.Get(hash2)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read commit %s: %w", hash2, err)
+	}
+	if hash1 == hash2 {
+		return []plumbing.Hash{hash1}, nil
+	}
+
+	candidates, err := newPainter().paintDownToCommon(index, one, []commitgraph.CommitNode{two})
+	if err != nil {
+		return nil, err
+	}
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("no common ancestor found")
+	}
+	if candidates, err = removeRedundant(index, candidates); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return newerFirst(candidates[i], candidates[j])
+	})
+	bases := make([]plumbing.Hash, len(candidates))
+	for i, candidate := range candidates {
+		bases[i] = candidate.ID()
+	}
+	return bases, nil
+}
+
+// Flags painted on commits while looking for common ancestors
+const (
+	reachableFromOne uint8 = 1 << iota
+	reachableFromTwo
+	// stale commits are below an already found common ancestor
+	stale
+	result
+)
+
+// paintDownToCommon walks down from one and twos, newest first, painting each
+// commit with the sides it is reachable from. A commit reachable from both
+// sides is a common ancestor; everything below it is stale, and the walk
+// stops once only stale commits are left. It returns the common ancestors
+// that no other common ancestor reaches, although with commit dates out of
+// order (no generation numbers) some may still be redundant.
+func (p *painter) paintDownToCommon(index commitgraph.CommitNodeIndex, one commitgraph.CommitNode, twos []commitgraph.CommitNode) ([]commitgraph.CommitNode, error) {
+	p.paint(one, reachableFromOne)
+	for _, two := range twos {
+		p.paint(two, reachableFromTwo)
+	}
+
+	var found []commitgraph.CommitNode
+	for p.hasNonStale() {
+		commit := heap.Pop(&p.queue).(commitgraph.CommitNode)
+		flags := p.flags[commit.ID()] & (reachableFromOne | reachableFromTwo | stale)
+		if flags&(reachableFromOne|reachableFromTwo) == reachableFromOne|reachableFromTwo {
+			if p.flags[commit.ID()]&result == 0 {
+				p.flags[commit.ID()] |= result
+				found = append(found, commit)
+			}
+			flags |= stale
+		}
+		for _, parentHash := range commit.ParentHashes() {
+			if p.flags[parentHash]&flags == flags {
+				continue
+			}
+			parent, err := index.Get(parentHash)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read commit %s: %w", parentHash, err)
+			}
+			p.paint(parent, flags)
 		}
-		queue = append(queue, commit.ParentHashes...)
 	}
 
-	queue2 := []plumbing.Hash{hash2}
-	for len(queue2) > 0 {
-		h := queue2[0]
-		queue2 = queue2[1:]
-		if _, ok := seen[h]; ok {
-			return h, nil
+	var common []commitgraph.CommitNode
+	for _, commit := range found {
+		if p.flags[commit.ID()]&stale == 0 {
+			common = append(common, commit)
 		}
-		commit, err := repo.CommitObject(h)
-		if err != nil {
+	}
+	return common, nil
+}
+
+// removeRedundant drops candidates that are ancestors of another candidate,
+// which criss-cross merges and clock skew can leave behind
+func removeRedundant(index commitgraph.CommitNodeIndex, candidates []commitgraph.CommitNode) ([]commitgraph.CommitNode, error) {
+	if len(candidates) < 2 {
+		return candidates, nil
+	}
+
+	redundant := make([]bool, len(candidates))
+	for i, candidate := range candidates {
+		if redundant[i] {
 			continue
 		}
-		queue2 = append(queue2, commit.ParentHashes...)
+		var others []commitgraph.CommitNode
+		var otherIndexes []int
+		for j, other := range candidates {
+			if j != i && !redundant[j] {
+				others = append(others, other)
+				otherIndexes = append(otherIndexes, j)
+			}
+		}
+
+		// Painting down from the candidate and the others marks which reach which
+		p := newPainter()
+		if _, err := p.paintDownToCommon(index, candidate, others); err != nil {
+			return nil, err
+		}
+		if p.flags[candidate.ID()]&reachableFromTwo != 0 {
+			redundant[i] = true
+		}
+		for k, other := range others {
+			if p.flags[other.ID()]&reachableFromOne != 0 {
+				redundant[otherIndexes[k]] = true
+			}
+		}
+	}
+
+	var kept []commitgraph.CommitNode
+	for i, candidate := range candidates {
+		if !redundant[i] {
+			kept = append(kept, candidate)
+		}
+	}
+	return kept, nil
+}
+
+// painter holds the flags and queue of one paint walk
+type painter struct {
+	flags map[plumbing.Hash]uint8
+	queue commitQueue
+}
+
+func newPainter() *painter {
+	return &painter{flags: map[plumbing.Hash]uint8{}}
+}
+
+func (p *painter) paint(commit commitgraph.CommitNode, flags uint8) {
+	p.flags[commit.ID()] |= flags
+	heap.Push(&p.queue, commit)
+}
+
+func (p *painter) hasNonStale() bool {
+	for _, commit := range p.queue {
+		if p.flags[commit.ID()]&stale == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// commitQueue is a max-heap of commits, children before their parents:
+// ordered by generation number and then commit date
+type commitQueue []commitgraph.CommitNode
+
+func (q commitQueue) Len() int           { return len(q) }
+func (q commitQueue) Less(i, j int) bool { return newerFirst(q[i], q[j]) }
+func (q commitQueue) Swap(i, j int)      { q[i], q[j] = q[j], q[i] }
+func (q *commitQueue) Push(x any)        { *q = append(*q, x.(commitgraph.CommitNode)) }
+func (q *commitQueue) Pop() any {
+	old := *q
+	commit := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return commit
+}
+
+// newerFirst orders commits by generation, then date, then hash. A commit's
+// generation is always above its parents', and commits missing from the
+// commit-graph report the highest generation.
+func newerFirst(a, b commitgraph.CommitNode) bool {
+	if genA, genB := a.Generation(), b.Generation(); genA != genB {
+		return genA > genB
+	}
+	if timeA, timeB := a.CommitTime(), b.CommitTime(); !timeA.Equal(timeB) {
+		return timeA.After(timeB)
 	}
-	return plumbing.ZeroHash, fmt.Errorf("no common ancestor found")
+	return a.ID().String() < b.ID().String()
 }
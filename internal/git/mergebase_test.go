@@ -0,0 +1,127 @@
+package git
+
+import (
+	"testing"
+	"time"
+
+	"github.com/frfahim/gitstory/internal/testutil"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object/commitgraph"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mergeBaseHistory builds:
+//
+//	A - B - C            main
+//	|        \
+//	|         X
+//	 \         \
+//	  ---------- F       F's first parent is A, its second X
+//
+// plus a criss-cross between P and Q:
+//
+//	C - P - P2 (merges Q)
+//	  \   X
+//	    Q - Q2 (merges P)
+type mergeBaseHistory struct {
+	a, b, c, x, f      plumbing.Hash
+	p, q, p2, q2, root plumbing.Hash
+}
+
+func createMergeBaseHistory(t *testing.T) (*testutil.TestRepo, mergeBaseHistory) {
+	testRepo := testutil.CreateTestRepo(t)
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	commit := func(message string, hours int, parents ...plumbing.Hash) plumbing.Hash {
+		return testutil.CommitWithParents(t, testRepo.Repo, message, start.Add(time.Duration(hours)*time.Hour), parents...)
+	}
+
+	var h mergeBaseHistory
+	h.a = commit("A", 0)
+	h.b = commit("B", 1, h.a)
+	h.c = commit("C", 2, h.b)
+	h.x = commit("X", 3, h.c)
+	h.f = commit("F", 4, h.a, h.x)
+
+	h.p = commit("P", 5, h.c)
+	h.q = commit("Q", 6, h.c)
+	h.p2 = commit("P2", 7, h.p, h.q)
+	h.q2 = commit("Q2", 8, h.q, h.p)
+
+	h.root = commit("unrelated root", 9)
+	return testRepo, h
+}
+
+func TestFindMergeBase(t *testing.T) {
+	testRepo, h := createMergeBaseHistory(t)
+	defer testRepo.Cleanup()
+
+	for _, withGraph := range []bool{false, true} {
+		if withGraph {
+			testRepo.WriteCommitGraph(t, h.f, h.p2, h.q2, h.root)
+		}
+		repo, err := OpenRepository(testRepo.Dir)
+		require.NoError(t, err)
+
+		index, closeIndex := commitNodeIndex(repo.repo)
+		node, err := index.Get(h.c)
+		require.NoError(t, err)
+		assert.Equal(t, withGraph, node.Generation() == 3, "generation comes from the commit-graph")
+		closeIndex()
+
+		// The best ancestor, not the first one reached through F's first parent
+		base, err := FindMergeBase(repo.repo, h.c, h.f)
+		require.NoError(t, err)
+		assert.Equal(t, h.c, base, "commit-graph %v", withGraph)
+
+		base, err = FindMergeBase(repo.repo, h.b, h.x)
+		require.NoError(t, err)
+		assert.Equal(t, h.b, base)
+
+		base, err = FindMergeBase(repo.repo, h.c, h.c)
+		require.NoError(t, err)
+		assert.Equal(t, h.c, base)
+
+		// Criss-cross merges have two best common ancestors
+		bases, err := FindMergeBases(repo.repo, h.p2, h.q2)
+		require.NoError(t, err)
+		assert.Equal(t, []plumbing.Hash{h.q, h.p}, bases)
+		base, err = FindMergeBase(repo.repo, h.p2, h.q2)
+		require.NoError(t, err)
+		assert.Equal(t, h.q, base)
+
+		_, err = FindMergeBase(repo.repo, h.c, h.root)
+		assert.ErrorContains(t, err, "no common ancestor")
+	}
+}
+
+func TestFindMergeBase_MissingCommit(t *testing.T) {
+	testRepo, h := createMergeBaseHistory(t)
+	defer testRepo.Cleanup()
+
+	missing := plumbing.NewHash("1111111111111111111111111111111111111111")
+	orphan := testutil.CommitWithParents(t, testRepo.Repo, "orphan", time.Now(), missing)
+
+	_, err := FindMergeBase(testRepo.Repo, h.c, orphan)
+	assert.ErrorContains(t, err, missing.String())
+}
+
+func BenchmarkFindMergeBase(b *testing.B) {
+	history := testutil.CreateSyntheticHistory(b, 100_000)
+
+	for _, bench := range []struct {
+		name  string
+		index commitgraph.CommitNodeIndex
+	}{
+		{"objects", commitgraph.NewObjectCommitNodeIndex(history.Repo.Storer)},
+		{"commit-graph", commitgraph.NewGraphCommitNodeIndex(history.Graph, history.Repo.Storer)},
+	} {
+		b.Run(bench.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				bases, err := findMergeBases(bench.index, history.Main, history.Feature)
+				require.NoError(b, err)
+				require.Equal(b, []plumbing.Hash{history.Fork}, bases)
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package git
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Pathspec matches repository paths against git-style pathspecs. Plain
+// entries include paths; entries written as ":(exclude)pattern", ":!pattern"
+// or ":^pattern" exclude them. A pattern matches a path exactly, as a
+// directory prefix ("services/billing" matches "services/billing/api.go"),
+// or as a glob where "*" also crosses directories ("*.pb.go", "docs/**/*.md").
+type Pathspec struct {
+	include []*regexp.Regexp
+	exclude []*regexp.Regexp
+}
+
+// ParsePathspec compiles a list of pathspec entries. An empty list matches every path.
+func ParsePathspec(specs []string) (*Pathspec, error) {
+	ps := &Pathspec{}
+	for _, spec := range specs {
+		pattern, excluded, err := parsePathspecMagic(spec)
+		if err != nil {
+			return nil, err
+		}
+		if pattern == "" {
+			continue
+		}
+		re, err := pathspecRegexp(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pathspec '%s': %w", spec, err)
+		}
+		if excluded {
+			ps.exclude = append(ps.exclude, re)
+		} else {
+			ps.include = append(ps.include, re)
+		}
+	}
+	return ps, nil
+}
+
+// IsEmpty reports whether the pathspec matches every path
+func (ps *Pathspec) IsEmpty() bool {
+	return ps == nil || (len(ps.include) == 0 && len(ps.exclude) == 0)
+}
+
+// Matches reports whether path is selected by the pathspec
+func (ps *Pathspec) Matches(path string) bool {
+	if ps.IsEmpty() {
+		return true
+	}
+	for _, re := range ps.exclude {
+		if re.MatchString(path) {
+			return false
+		}
+	}
+	if len(ps.include) == 0 {
+		return true
+	}
+	for _, re := range ps.include {
+		if re.MatchString(path) {
+			return true
+		}
+	}
+	return false
+}
+
+// parsePathspecMagic strips the ":(exclude)" / ":!" / ":^" prefix from a pathspec entry
+func parsePathspecMagic(spec string) (pattern string, excluded bool, err error) {
+	switch {
+	case strings.HasPrefix(spec, ":(exclude)"):
+		return strings.TrimPrefix(spec, ":(exclude)"), true, nil
+	case strings.HasPrefix(spec, ":!"), strings.HasPrefix(spec, ":^"):
+		return spec[2:], true, nil
+	case strings.HasPrefix(spec, ":("):
+		return "", false, fmt.Errorf("unsupported pathspec magic in '%s' (only :(exclude) is supported)", spec)
+	case strings.HasPrefix(spec, ":/"):
+		// Paths are always relative to the repository root
+		return spec[2:], false, nil
+	}
+	return spec, false, nil
+}
+
+// pathspecRegexp translates a pathspec pattern into an anchored regular expression
+func pathspecRegexp(pattern string) (*regexp.Regexp, error) {
+	pattern = strings.TrimPrefix(pattern, "./")
+	pattern = strings.TrimSuffix(pattern, "/")
+
+	var expr strings.Builder
+	expr.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; c {
+		case '*':
+			// "**/" matches zero or more directories
+			if strings.HasPrefix(pattern[i:], "**/") {
+				expr.WriteString("(.*/)?")
+				i += 2
+				continue
+			}
+			expr.WriteString(".*")
+		case '?':
+			expr.WriteString("[^/]")
+		case '[':
+			end := strings.IndexByte(pattern[i:], ']')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated character class")
+			}
+			class := pattern[i+1 : i+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			expr.WriteString("[" + class + "]")
+			i += end
+		default:
+			expr.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	// A pattern also selects everything below it when it names a directory
+	expr.WriteString("(/.*)?$")
+	return regexp.Compile(expr.String())
+}
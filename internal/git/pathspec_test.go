@@ -0,0 +1,73 @@
+package git
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPathspecMatches(t *testing.T) {
+	tests := []struct {
+		spec     []string
+		path     string
+		expected bool
+	}{
+		{nil, "anything.go", true},
+		{[]string{"services/billing/"}, "services/billing/api.go", true},
+		{[]string{"services/billing"}, "services/billing/internal/db.go", true},
+		{[]string{"services/billing"}, "services/billing-v2/api.go", false},
+		{[]string{"*.go"}, "cmd/root.go", true},
+		{[]string{"docs/**/*.md"}, "docs/guide/intro.md", true},
+		{[]string{"docs/**/*.md"}, "docs/intro.md", true},
+		{[]string{"file?.txt"}, "file1.txt", true},
+		{[]string{"file?.txt"}, "file10.txt", false},
+		{[]string{"services/", ":(exclude)*.pb.go"}, "services/api.pb.go", false},
+		{[]string{"services/", ":!*_test.go"}, "services/api.go", true},
+		{[]string{":^vendor"}, "vendor/lib/x.go", false},
+		{[]string{":^vendor"}, "main.go", true},
+	}
+	for _, tt := range tests {
+		ps, err := ParsePathspec(tt.spec)
+		require.NoError(t, err)
+		assert.Equal(t, tt.expected, ps.Matches(tt.path), "spec %v path %s", tt.spec, tt.path)
+	}
+}
+
+func TestParsePathspec_UnsupportedMagic(t *testing.T) {
+	_, err := ParsePathspec([]string{":(icase)README"})
+	assert.Error(t, err)
+}
+
+func TestPathFiltering(t *testing.T) {
+	repo, testRepo := setupEmptyTestRepo(t)
+	defer testRepo.Cleanup()
+	testRepo.AddCommit(t, "README.md", "# Test", "docs")
+	testRepo.AddCommit(t, "billing.go", "package billing", "billing")
+	testRepo.AddCommit(t, "billing.pb.go", "package billing // generated", "generated")
+
+	commits, err := repo.ListCommitsWithOptions(ListOptions{Paths: []string{"billing*", ":(exclude)*.pb.go"}})
+	require.NoError(t, err)
+	require.Len(t, commits, 1)
+	assert.Equal(t, "billing", commits[0].Message)
+
+	all, err := repo.ListCommits(10)
+	require.NoError(t, err)
+	summaries, err := repo.ListCommitSummarize(all, DiffOptions{Paths: []string{"*.md"}})
+	require.NoError(t, err)
+	for _, s := range summaries {
+		for _, f := range s.Files {
+			assert.Equal(t, "README.md", f.Path)
+		}
+	}
+
+	// A file renamed out of the pathspec is still listed under its new name
+	testRepo.MoveFile(t, "billing.go", "payments.go", "rename")
+	moved, err := repo.ListCommits(1)
+	require.NoError(t, err)
+	summaries, err = repo.ListCommitSummarize(moved, DiffOptions{Paths: []string{"billing.go"}})
+	require.NoError(t, err)
+	require.Len(t, summaries[0].Files, 1)
+	assert.Equal(t, "payments.go", summaries[0].Files[0].Path)
+	assert.Equal(t, "billing.go", summaries[0].Files[0].OldPath)
+}
@@ -2,6 +2,7 @@ package git
 
 import (
 	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/storage/filesystem"
 )
 
 // Repository holds a git.Repository and its path
@@ -32,3 +33,13 @@ func (r *Repository) CurrentBranchName() string {
 	}
 	return head.Name().Short()
 }
+
+// GitDir returns the path of the .git directory, or "" when the repository
+// isn't stored on disk
+func (r *Repository) GitDir() string {
+	storage, ok := r.repo.Storer.(*filesystem.Storage)
+	if !ok {
+		return ""
+	}
+	return storage.Filesystem().Root()
+}
@@ -0,0 +1,188 @@
+package git
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/storer"
+)
+
+// RevisionRange is a parsed revision range such as "v1.2.0..v1.3.0",
+// "main...feature" or a single revision like "HEAD~10"
+type RevisionRange struct {
+	// From is the excluded side of the range; empty for a single revision
+	From string
+	// To is the included side of the range
+	To string
+	// Symmetric is true for "A...B": commits reachable from either side but not both
+	Symmetric bool
+}
+
+// ParseRevisionRange parses "A..B", "A...B" or a single revision "B".
+// An omitted side defaults to HEAD, so "v1.2.0.." means "v1.2.0..HEAD".
+func ParseRevisionRange(spec string) (RevisionRange, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return RevisionRange{}, fmt.Errorf("empty revision range")
+	}
+
+	sep := ".."
+	rr := RevisionRange{}
+	if strings.Contains(spec, "...") {
+		sep = "..."
+		rr.Symmetric = true
+	}
+	if !strings.Contains(spec, sep) {
+		return RevisionRange{To: spec}, nil
+	}
+
+	parts := strings.SplitN(spec, sep, 2)
+	rr.From, rr.To = parts[0], parts[1]
+	if rr.From == "" {
+		rr.From = "HEAD"
+	}
+	if rr.To == "" {
+		rr.To = "HEAD"
+	}
+	if strings.Contains(rr.To, "..") {
+		return RevisionRange{}, fmt.Errorf("invalid revision range %q", spec)
+	}
+	return rr, nil
+}
+
+// String formats the range the way git would
+func (rr RevisionRange) String() string {
+	if rr.From == "" {
+		return rr.To
+	}
+	if rr.Symmetric {
+		return rr.From + "..." + rr.To
+	}
+	return rr.From + ".." + rr.To
+}
+
+// ResolveRevision resolves a branch, remote branch, tag, (short) SHA or
+// expression like HEAD~3 to a commit hash
+func (r *Repository) ResolveRevision(rev string) (plumbing.Hash, error) {
+	hash, err := r.repo.ResolveRevision(plumbing.Revision(rev))
+	if err != nil {
+		return plumbing.ZeroHash, fmt.Errorf("unknown revision '%s': %w", rev, err)
+	}
+	return *hash, nil
+}
+
+// ListRangeCommits returns the commits selected by a revision range spec
+// (see ParseRevisionRange) that match opts, newest first
+func (r *Repository) ListRangeCommits(spec string, opts ListOptions) ([]*object.Commit, error) {
+	rr, err := ParseRevisionRange(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	to, err := r.commitForRevision(rr.To)
+	if err != nil {
+		return nil, err
+	}
+	if rr.From == "" {
+		return r.collectCommits(object.NewCommitIterCTime(to, nil, nil), plumbing.ZeroHash, opts)
+	}
+
+	from, err := r.commitForRevision(rr.From)
+	if err != nil {
+		return nil, err
+	}
+
+	excluded, err := r.ancestors(from)
+	if err != nil {
+		return nil, err
+	}
+	if !rr.Symmetric {
+		// Walking from `to` never descends into anything reachable from `from`
+		return r.collectCommits(object.NewCommitIterCTime(to, excluded, nil), plumbing.ZeroHash, opts)
+	}
+
+	// A...B: everything reachable from exactly one side
+	included, err := r.ancestors(to)
+	if err != nil {
+		return nil, err
+	}
+	var commits []*object.Commit
+	for hash := range included {
+		if !excluded[hash] {
+			c, err := r.repo.CommitObject(hash)
+			if err != nil {
+				return nil, err
+			}
+			commits = append(commits, c)
+		}
+	}
+	for hash := range excluded {
+		if !included[hash] {
+			c, err := r.repo.CommitObject(hash)
+			if err != nil {
+				return nil, err
+			}
+			commits = append(commits, c)
+		}
+	}
+	sort.SliceStable(commits, func(i, j int) bool {
+		return commits[i].Committer.When.After(commits[j].Committer.When)
+	})
+	return r.collectCommits(&commitSliceIter{commits: commits}, plumbing.ZeroHash, opts)
+}
+
+func (r *Repository) commitForRevision(rev string) (*object.Commit, error) {
+	hash, err := r.ResolveRevision(rev)
+	if err != nil {
+		return nil, err
+	}
+	return r.repo.CommitObject(hash)
+}
+
+// ancestors returns the set of commits reachable from c, including c itself
+func (r *Repository) ancestors(c *object.Commit) (map[plumbing.Hash]bool, error) {
+	seen := map[plumbing.Hash]bool{}
+	err := object.NewCommitPreorderIter(c, nil, nil).ForEach(func(c *object.Commit) error {
+		seen[c.Hash] = true
+		return nil
+	})
+	return seen, err
+}
+
+// commitSliceIter adapts an ordered slice of commits to object.CommitIter
+type commitSliceIter struct {
+	commits []*object.Commit
+	pos     int
+}
+
+func (it *commitSliceIter) Next() (*object.Commit, error) {
+	if it.pos >= len(it.commits) {
+		return nil, io.EOF
+	}
+	c := it.commits[it.pos]
+	it.pos++
+	return c, nil
+}
+
+func (it *commitSliceIter) ForEach(cb func(*object.Commit) error) error {
+	for {
+		c, err := it.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err := cb(c); err != nil {
+			if err == storer.ErrStop {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+func (it *commitSliceIter) Close() {
+	it.pos = len(it.commits)
+}
@@ -0,0 +1,85 @@
+package git
+
+import (
+	"testing"
+	"time"
+
+	"github.com/frfahim/gitstory/internal/testutil"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRevisionRange(t *testing.T) {
+	tests := map[string]RevisionRange{
+		"HEAD~3":         {To: "HEAD~3"},
+		"v1.2.0..v1.3.0": {From: "v1.2.0", To: "v1.3.0"},
+		"v1.2.0..":       {From: "v1.2.0", To: "HEAD"},
+		"..feature":      {From: "HEAD", To: "feature"},
+		"main...feature": {From: "main", To: "feature", Symmetric: true},
+	}
+	for spec, expected := range tests {
+		rr, err := ParseRevisionRange(spec)
+		require.NoError(t, err, spec)
+		assert.Equal(t, expected, rr, spec)
+	}
+
+	_, err := ParseRevisionRange("")
+	assert.Error(t, err)
+}
+
+func commitMessages(t *testing.T, repo *Repository, spec string) []string {
+	commits, err := repo.ListRangeCommits(spec, ListOptions{})
+	require.NoError(t, err, spec)
+	var messages []string
+	for _, c := range commits {
+		messages = append(messages, c.Message)
+	}
+	return messages
+}
+
+func TestListRangeCommits(t *testing.T) {
+	testRepo := testutil.CreateTestRepo(t)
+	defer testRepo.Cleanup()
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	testRepo.AddCommitAt(t, "a.txt", "a", "one", start)
+	testRepo.AddCommitAt(t, "b.txt", "b", "two", start.Add(time.Hour))
+
+	head, err := testRepo.Repo.Head()
+	require.NoError(t, err)
+	_, err = testRepo.Repo.CreateTag("v1.0.0", head.Hash(), &git.CreateTagOptions{
+		Message: "v1.0.0",
+		Tagger:  &object.Signature{Name: "Test User", Email: "test@example.com", When: start},
+	})
+	require.NoError(t, err)
+
+	testRepo.AddCommitAt(t, "c.txt", "c", "three", start.Add(2*time.Hour))
+	testRepo.AddCommitAt(t, "d.txt", "d", "four", start.Add(3*time.Hour))
+
+	// Diverging branch off v1.0.0
+	worktree, err := testRepo.Repo.Worktree()
+	require.NoError(t, err)
+	require.NoError(t, worktree.Checkout(&git.CheckoutOptions{
+		Hash:   head.Hash(),
+		Branch: plumbing.NewBranchReferenceName("feature"),
+		Create: true,
+	}))
+	testRepo.AddCommitAt(t, "f.txt", "f", "feature work", start.Add(4*time.Hour))
+
+	repo, err := OpenRepository(testRepo.Dir)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"four", "three"}, commitMessages(t, repo, "v1.0.0..master"))
+	assert.Equal(t, []string{"feature work"}, commitMessages(t, repo, "v1.0.0.."))
+	assert.Equal(t, []string{"feature work", "four", "three"}, commitMessages(t, repo, "master...feature"))
+	assert.Equal(t, []string{"three", "two", "one"}, commitMessages(t, repo, "master~1"))
+
+	short := head.Hash().String()[:7]
+	assert.Equal(t, []string{"four", "three"}, commitMessages(t, repo, short+"..master"))
+
+	_, err = repo.ListRangeCommits("nope..master", ListOptions{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,272 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/go-git/go-billy/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/format/index"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/storer"
+)
+
+// StagedChanges returns the changes staged in the index against HEAD, the
+// changes `git commit` would record. With worktree set, tracked files are
+// read from the worktree instead, like `git commit --all`. Untracked files
+// are left out either way. Nothing is written to the repository.
+func (r *Repository) StagedChanges(worktree bool, opts DiffOptions) (CommitDiffDetails, error) {
+	if opts.contentFilter == nil {
+		filter, err := r.NewContentFilter(opts.ContentExcludes, opts.AllContent)
+		if err != nil {
+			return CommitDiffDetails{}, err
+		}
+		opts.contentFilter = filter
+	}
+
+	headTree, err := r.headTree()
+	if err != nil {
+		return CommitDiffDetails{}, err
+	}
+	idx, err := r.repo.Storer.Index()
+	if err != nil {
+		return CommitDiffDetails{}, fmt.Errorf("failed to read the index: %w", err)
+	}
+	var entries []*index.Entry
+	for _, entry := range idx.Entries {
+		// Merged entries are stage 0; go-git's index.Merged constant is misnumbered
+		if entry.Stage != 0 {
+			return CommitDiffDetails{}, fmt.Errorf("the index has unresolved conflicts in %s", entry.Name)
+		}
+		entries = append(entries, entry)
+	}
+
+	overlay := newOverlayStorer(r.repo.Storer)
+	if worktree {
+		if entries, err = r.worktreeEntries(entries, overlay); err != nil {
+			return CommitDiffDetails{}, err
+		}
+	}
+	tree, err := buildTree(overlay, entries)
+	if err != nil {
+		return CommitDiffDetails{}, err
+	}
+
+	changes, err := treeChanges(headTree, tree)
+	if err != nil {
+		return CommitDiffDetails{}, err
+	}
+	files, stats, err := r.summarizeChanges(changes, opts)
+	if err != nil {
+		return CommitDiffDetails{}, err
+	}
+	opts.contentFilter.apply(files)
+	return CommitDiffDetails{Files: files, Stats: stats}, nil
+}
+
+// headTree returns the tree of HEAD, or nil (the empty tree) before the first commit
+func (r *Repository) headTree() (*object.Tree, error) {
+	head, err := r.repo.Head()
+	if errors.Is(err, plumbing.ErrReferenceNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve HEAD: %w", err)
+	}
+	commit, err := r.repo.CommitObject(head.Hash())
+	if err != nil {
+		return nil, fmt.Errorf("failed to read HEAD commit: %w", err)
+	}
+	return commit.Tree()
+}
+
+// worktreeEntries replaces index entries with the worktree's version of each
+// file, dropping deleted ones. New contents are written to s. Files whose size
+// and modification time match the index are assumed unchanged, as git does.
+func (r *Repository) worktreeEntries(entries []*index.Entry, s storer.EncodedObjectStorer) ([]*index.Entry, error) {
+	wt, err := r.repo.Worktree()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open the worktree: %w", err)
+	}
+	fs := wt.Filesystem
+
+	var updated []*index.Entry
+	for _, entry := range entries {
+		if entry.Mode == filemode.Submodule || entry.SkipWorktree {
+			updated = append(updated, entry)
+			continue
+		}
+		info, err := fs.Lstat(entry.Name)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to stat %s: %w", entry.Name, err)
+		}
+		if info.IsDir() {
+			continue
+		}
+		mode, err := filemode.NewFromOSFileMode(info.Mode())
+		if err != nil {
+			mode = entry.Mode
+		}
+		if mode == entry.Mode && uint32(info.Size()) == entry.Size && info.ModTime().Equal(entry.ModifiedAt) {
+			updated = append(updated, entry)
+			continue
+		}
+
+		hash, err := writeWorktreeBlob(s, fs, entry.Name, mode)
+		if err != nil {
+			return nil, err
+		}
+		updated = append(updated, &index.Entry{Name: entry.Name, Mode: mode, Hash: hash})
+	}
+	return updated, nil
+}
+
+// writeWorktreeBlob stores a worktree file, or a symlink's target, as a blob in s
+func writeWorktreeBlob(s storer.EncodedObjectStorer, fs billy.Filesystem, name string, mode filemode.FileMode) (plumbing.Hash, error) {
+	if mode == filemode.Symlink {
+		target, err := fs.Readlink(name)
+		if err != nil {
+			return plumbing.ZeroHash, fmt.Errorf("failed to read link %s: %w", name, err)
+		}
+		return writeBlob(s, strings.NewReader(target))
+	}
+
+	file, err := fs.Open(name)
+	if err != nil {
+		return plumbing.ZeroHash, fmt.Errorf("failed to open %s: %w", name, err)
+	}
+	defer file.Close()
+	hash, err := writeBlob(s, file)
+	if err != nil {
+		return plumbing.ZeroHash, fmt.Errorf("failed to read %s: %w", name, err)
+	}
+	return hash, nil
+}
+
+// writeBlob stores content as a blob in s
+func writeBlob(s storer.EncodedObjectStorer, content io.Reader) (plumbing.Hash, error) {
+	obj := s.NewEncodedObject()
+	obj.SetType(plumbing.BlobObject)
+	w, err := obj.Writer()
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+	if _, err := io.Copy(w, content); err != nil {
+		w.Close()
+		return plumbing.ZeroHash, err
+	}
+	if err := w.Close(); err != nil {
+		return plumbing.ZeroHash, err
+	}
+	return s.SetEncodedObject(obj)
+}
+
+// treeBuilder collects the entries of one directory while building trees
+type treeBuilder struct {
+	entries []object.TreeEntry
+	dirs    map[string]*treeBuilder
+}
+
+// buildTree writes the trees holding entries to s and returns the root tree
+func buildTree(s storer.EncodedObjectStorer, entries []*index.Entry) (*object.Tree, error) {
+	root := &treeBuilder{dirs: map[string]*treeBuilder{}}
+	for _, entry := range entries {
+		dir := root
+		parts := strings.Split(entry.Name, "/")
+		for _, name := range parts[:len(parts)-1] {
+			child, ok := dir.dirs[name]
+			if !ok {
+				child = &treeBuilder{dirs: map[string]*treeBuilder{}}
+				dir.dirs[name] = child
+			}
+			dir = child
+		}
+		dir.entries = append(dir.entries, object.TreeEntry{Name: path.Base(entry.Name), Mode: entry.Mode, Hash: entry.Hash})
+	}
+
+	hash, err := root.write(s)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build the index tree: %w", err)
+	}
+	return object.GetTree(s, hash)
+}
+
+// write stores the directory's tree, after those of its subdirectories
+func (b *treeBuilder) write(s storer.EncodedObjectStorer) (plumbing.Hash, error) {
+	entries := append([]object.TreeEntry{}, b.entries...)
+	for name, dir := range b.dirs {
+		hash, err := dir.write(s)
+		if err != nil {
+			return plumbing.ZeroHash, err
+		}
+		entries = append(entries, object.TreeEntry{Name: name, Mode: filemode.Dir, Hash: hash})
+	}
+	// Git orders directories as if their names ended in "/"
+	sortName := func(entry object.TreeEntry) string {
+		if entry.Mode == filemode.Dir {
+			return entry.Name + "/"
+		}
+		return entry.Name
+	}
+	sort.Slice(entries, func(i, j int) bool { return sortName(entries[i]) < sortName(entries[j]) })
+
+	obj := s.NewEncodedObject()
+	if err := (&object.Tree{Entries: entries}).Encode(obj); err != nil {
+		return plumbing.ZeroHash, err
+	}
+	return s.SetEncodedObject(obj)
+}
+
+// overlayStorer keeps the objects it is given in memory and reads the rest
+// from the repository, so uncommitted trees can be diffed like commits
+// without writing to .git
+type overlayStorer struct {
+	storer.EncodedObjectStorer
+	objects map[plumbing.Hash]plumbing.EncodedObject
+}
+
+func newOverlayStorer(base storer.EncodedObjectStorer) *overlayStorer {
+	return &overlayStorer{EncodedObjectStorer: base, objects: map[plumbing.Hash]plumbing.EncodedObject{}}
+}
+
+func (s *overlayStorer) NewEncodedObject() plumbing.EncodedObject {
+	return &plumbing.MemoryObject{}
+}
+
+func (s *overlayStorer) SetEncodedObject(obj plumbing.EncodedObject) (plumbing.Hash, error) {
+	s.objects[obj.Hash()] = obj
+	return obj.Hash(), nil
+}
+
+func (s *overlayStorer) EncodedObject(t plumbing.ObjectType, hash plumbing.Hash) (plumbing.EncodedObject, error) {
+	if obj, ok := s.objects[hash]; ok {
+		if t != plumbing.AnyObject && obj.Type() != t {
+			return nil, plumbing.ErrObjectNotFound
+		}
+		return obj, nil
+	}
+	return s.EncodedObjectStorer.EncodedObject(t, hash)
+}
+
+func (s *overlayStorer) HasEncodedObject(hash plumbing.Hash) error {
+	if _, ok := s.objects[hash]; ok {
+		return nil
+	}
+	return s.EncodedObjectStorer.HasEncodedObject(hash)
+}
+
+func (s *overlayStorer) EncodedObjectSize(hash plumbing.Hash) (int64, error) {
+	if obj, ok := s.objects[hash]; ok {
+		return obj.Size(), nil
+	}
+	return s.EncodedObjectStorer.EncodedObjectSize(hash)
+}
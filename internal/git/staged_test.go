@@ -0,0 +1,94 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/frfahim/gitstory/internal/types"
+	"github.com/go-git/go-git/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// changesByPath indexes file changes by path
+func changesByPath(files []types.FileChange) map[string]types.FileChange {
+	byPath := map[string]types.FileChange{}
+	for _, file := range files {
+		byPath[file.Path] = file
+	}
+	return byPath
+}
+
+func TestStagedChanges(t *testing.T) {
+	repo, testRepo := setupTestRepo(t)
+	defer testRepo.Cleanup()
+	worktree, err := testRepo.Repo.Worktree()
+	require.NoError(t, err)
+	write := func(name, content string) {
+		path := filepath.Join(testRepo.Dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+
+	// Staged: a modification, a new file in a new directory and a rename
+	write("main.go", "package main\n\nfunc main() { run() }\n")
+	write("internal/run.go", "package main\n\nfunc run() {}\n")
+	for _, name := range []string{"main.go", "internal/run.go"} {
+		_, err = worktree.Add(name)
+		require.NoError(t, err)
+	}
+	_, err = worktree.Move("config.yaml", "settings.yaml")
+	require.NoError(t, err)
+	// Unstaged: a modification, a deletion and an untracked file
+	write("README.md", "# Test Project\n\nMore docs\n")
+	write("main.go", "package main\n\nfunc main() { run(); run() }\n")
+	write("notes.txt", "untracked\n")
+
+	details, err := repo.StagedChanges(false, DiffOptions{IncludeDiff: true})
+	require.NoError(t, err)
+	staged := changesByPath(details.Files)
+	require.Len(t, staged, 3)
+	assert.Equal(t, "Modify", staged["main.go"].Status)
+	assert.Contains(t, staged["main.go"].Content, "+func main() { run() }")
+	assert.Equal(t, "Insert", staged["internal/run.go"].Status)
+	assert.Equal(t, types.StatusRenamed, staged["settings.yaml"].Status)
+	assert.Equal(t, "config.yaml", staged["settings.yaml"].OldPath)
+
+	require.NoError(t, os.Remove(filepath.Join(testRepo.Dir, "internal/run.go")))
+	details, err = repo.StagedChanges(true, DiffOptions{IncludeDiff: true})
+	require.NoError(t, err)
+	all := changesByPath(details.Files)
+	require.Len(t, all, 3)
+	assert.Equal(t, "Modify", all["README.md"].Status)
+	assert.Contains(t, all["main.go"].Content, "+func main() { run(); run() }")
+	assert.Equal(t, types.StatusRenamed, all["settings.yaml"].Status)
+	assert.NotContains(t, all, "internal/run.go")
+	assert.NotContains(t, all, "notes.txt")
+
+	// Nothing was written to the repository
+	status, err := worktree.Status()
+	require.NoError(t, err)
+	assert.Equal(t, git.Added, status.File("internal/run.go").Staging)
+	assert.Equal(t, git.Modified, status.File("main.go").Worktree)
+}
+
+func TestStagedChanges_NoCommits(t *testing.T) {
+	repo, testRepo := setupEmptyTestRepo(t)
+	defer testRepo.Cleanup()
+	worktree, err := testRepo.Repo.Worktree()
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(testRepo.Dir, "README.md"), []byte("# New\n"), 0644))
+
+	details, err := repo.StagedChanges(false, DiffOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, details.Files)
+
+	_, err = worktree.Add("README.md")
+	require.NoError(t, err)
+	details, err = repo.StagedChanges(false, DiffOptions{})
+	require.NoError(t, err)
+	require.Len(t, details.Files, 1)
+	assert.Equal(t, "Insert", details.Files[0].Status)
+	assert.Equal(t, 1, details.Stats.Additions)
+}
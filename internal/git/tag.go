@@ -0,0 +1,106 @@
+package git
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// Tag is a tag pointing, directly or through an annotated tag, at a commit
+type Tag struct {
+	Name string
+	// Hash is the tagged commit
+	Hash plumbing.Hash
+	// Date is the tagger date of an annotated tag, else the commit date
+	Date time.Time
+}
+
+// Tags returns the tags that point at commits, oldest first. Tags of trees
+// and blobs are skipped.
+func (r *Repository) Tags() ([]Tag, error) {
+	refs, err := r.repo.Tags()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tags: %w", err)
+	}
+
+	var tags []Tag
+	err = refs.ForEach(func(ref *plumbing.Reference) error {
+		tag, ok, err := r.resolveTag(ref)
+		if ok {
+			tags = append(tags, tag)
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(tags, func(i, j int) bool {
+		if !tags[i].Date.Equal(tags[j].Date) {
+			return tags[i].Date.Before(tags[j].Date)
+		}
+		return tags[i].Name < tags[j].Name
+	})
+	return tags, nil
+}
+
+// TagsReachableFrom returns the tags of rev and its ancestors, oldest first
+func (r *Repository) TagsReachableFrom(rev string) ([]Tag, error) {
+	commit, err := r.commitForRevision(rev)
+	if err != nil {
+		return nil, err
+	}
+	reachable, err := r.ancestors(commit)
+	if err != nil {
+		return nil, err
+	}
+	tags, err := r.Tags()
+	if err != nil {
+		return nil, err
+	}
+
+	var filtered []Tag
+	for _, tag := range tags {
+		if reachable[tag.Hash] {
+			filtered = append(filtered, tag)
+		}
+	}
+	return filtered, nil
+}
+
+// resolveTag peels a tag reference to its commit; ok is false when it
+// doesn't point at a commit
+func (r *Repository) resolveTag(ref *plumbing.Reference) (Tag, bool, error) {
+	tag := Tag{Name: ref.Name().Short()}
+	hash := ref.Hash()
+
+	annotated, err := r.repo.TagObject(hash)
+	switch err {
+	case nil:
+		if annotated.TargetType != plumbing.CommitObject {
+			return tag, false, nil
+		}
+		tag.Date = annotated.Tagger.When
+		hash = annotated.Target
+	case plumbing.ErrObjectNotFound, object.ErrUnsupportedObject:
+		// A lightweight tag points straight at its object
+	default:
+		return tag, false, fmt.Errorf("failed to read tag %s: %w", tag.Name, err)
+	}
+
+	commit, err := r.repo.CommitObject(hash)
+	if err == plumbing.ErrObjectNotFound || err == object.ErrUnsupportedObject {
+		return tag, false, nil
+	}
+	if err != nil {
+		return tag, false, fmt.Errorf("failed to read commit of tag %s: %w", tag.Name, err)
+	}
+	tag.Hash = commit.Hash
+	if tag.Date.IsZero() {
+		tag.Date = commit.Committer.When
+	}
+	return tag, true, nil
+}
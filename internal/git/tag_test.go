@@ -0,0 +1,66 @@
+package git
+
+import (
+	"testing"
+	"time"
+
+	"github.com/frfahim/gitstory/internal/testutil"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTags(t *testing.T) {
+	testRepo := testutil.CreateTestRepo(t)
+	defer testRepo.Cleanup()
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	head := func() plumbing.Hash {
+		ref, err := testRepo.Repo.Head()
+		require.NoError(t, err)
+		return ref.Hash()
+	}
+	testRepo.AddCommitAt(t, "a.txt", "a", "first", start)
+	first := head()
+	_, err := testRepo.Repo.CreateTag("v0.1.0", first, nil)
+	require.NoError(t, err)
+
+	testRepo.AddCommitAt(t, "b.txt", "b", "second", start.Add(time.Hour))
+	second := head()
+	tagged := start.Add(48 * time.Hour)
+	_, err = testRepo.Repo.CreateTag("v0.2.0", second, &git.CreateTagOptions{
+		Tagger:  &object.Signature{Name: "Test User", Email: "test@example.com", When: tagged},
+		Message: "Release 0.2.0",
+	})
+	require.NoError(t, err)
+
+	// A tag on another branch isn't reachable from HEAD
+	side := testutil.CommitWithParents(t, testRepo.Repo, "side", start.Add(2*time.Hour), first)
+	_, err = testRepo.Repo.CreateTag("v0.1.1", side, nil)
+	require.NoError(t, err)
+
+	// Tags of trees aren't releases
+	commit, err := testRepo.Repo.CommitObject(second)
+	require.NoError(t, err)
+	_, err = testRepo.Repo.CreateTag("tree", commit.TreeHash, nil)
+	require.NoError(t, err)
+
+	repo, err := OpenRepository(testRepo.Dir)
+	require.NoError(t, err)
+
+	tags, err := repo.Tags()
+	require.NoError(t, err)
+	var names []string
+	for _, tag := range tags {
+		names = append(names, tag.Name)
+	}
+	assert.Equal(t, []string{"v0.1.0", "v0.1.1", "v0.2.0"}, names)
+	assert.Equal(t, second, tags[2].Hash, "annotated tags point at their commit")
+	assert.True(t, tags[2].Date.Equal(tagged), "annotated tags are dated by their tagger")
+
+	reachable, err := repo.TagsReachableFrom("HEAD")
+	require.NoError(t, err)
+	assert.Equal(t, []Tag{tags[0], tags[2]}, reachable)
+}
@@ -1,23 +1,172 @@
 package llm
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
 )
 
+const (
+	defaultClaudeBaseURL = "https://api.anthropic.com"
+	claudeAPIVersion     = "2023-06-01"
+)
+
+// ClaudeClient implements the Client interface for Anthropic's Messages API
 type ClaudeClient struct {
-	client interface{} // Placeholder for actual Claude client type
-	config ClientConfig
+	httpClient *http.Client
+	baseURL    string
+	config     ClientConfig
 }
 
+// GetProvider returns the provider type
 func (c *ClaudeClient) GetProvider() Provider {
 	return Claude
 }
 
+// NewClaudeClient creates a new Claude client talking to the Messages API
 func NewClaudeClient(config ClientConfig) (*ClaudeClient, error) {
-	return nil, fmt.Errorf("claude isn't yet implemented")
+	if config.APIKey == "" {
+		return nil, fmt.Errorf("Claude API key is required")
+	}
+	if config.Model == "" {
+		config.Model = getDefaultModel(Claude)
+	}
+
+	// Base URL priority: config -> ANTHROPIC_BASE_URL -> public API
+	baseURL := config.BaseURL
+	if baseURL == "" {
+		baseURL = strings.TrimSpace(os.Getenv("ANTHROPIC_BASE_URL"))
+	}
+	if baseURL == "" {
+		baseURL = defaultClaudeBaseURL
+	}
+
+	return &ClaudeClient{
+		httpClient: http.DefaultClient,
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		config:     config,
+	}, nil
+}
+
+// claudeMessage is a single turn in a Messages API conversation
+type claudeMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// claudeRequest is the body sent to POST /v1/messages
+type claudeRequest struct {
+	Model       string          `json:"model"`
+	System      string          `json:"system,omitempty"`
+	Messages    []claudeMessage `json:"messages"`
+	MaxTokens   int             `json:"max_tokens"`
+	Temperature float64         `json:"temperature"`
+}
+
+// claudeResponse is the subset of the Messages API response we use
+type claudeResponse struct {
+	Content []struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	} `json:"content"`
+	StopReason string `json:"stop_reason"`
+}
+
+// claudeErrorResponse is the error envelope returned by the Messages API
+type claudeErrorResponse struct {
+	Error struct {
+		Type    string `json:"type"`
+		Message string `json:"message"`
+	} `json:"error"`
 }
 
 func (c *ClaudeClient) Summarize(ctx context.Context, request *SummaryRequest) (*SummaryResponse, error) {
-	return nil, fmt.Errorf("claude isn't yet implemented")
+	systemPrompt, userPrompt := providerPrompt(Claude, request)
+	body, err := json.Marshal(claudeRequest{
+		Model:  c.config.Model,
+		System: systemPrompt,
+		Messages: []claudeMessage{
+			{Role: "user", Content: userPrompt},
+		},
+		MaxTokens:   c.config.maxTokens(request.Platform),
+		Temperature: c.config.temperature(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode Claude request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/v1/messages", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Claude request: %w", err)
+	}
+	httpReq.Header.Set("content-type", "application/json")
+	httpReq.Header.Set("x-api-key", c.config.APIKey)
+	httpReq.Header.Set("anthropic-version", claudeAPIVersion)
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate summary: %w", classifyError(Claude, err))
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Claude response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, claudeAPIError(resp, respBody)
+	}
+
+	var result claudeResponse
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return nil, fmt.Errorf("failed to decode Claude response: %w", err)
+	}
+
+	var summary strings.Builder
+	for _, block := range result.Content {
+		if block.Type == "text" {
+			summary.WriteString(block.Text)
+		}
+	}
+	if summary.Len() == 0 {
+		if result.StopReason == "refusal" {
+			return nil, contentFilterError(Claude, result.StopReason)
+		}
+		return nil, fmt.Errorf("no text content returned from Claude API")
+	}
+
+	return &SummaryResponse{
+		Platform: request.Platform,
+		Summary:  strings.TrimSpace(summary.String()),
+		Provider: Claude,
+	}, nil
+}
+
+// claudeAPIError classifies a non-200 Messages API response
+func claudeAPIError(resp *http.Response, body []byte) *Error {
+	apiErr := &Error{
+		Kind:       kindForStatus(resp.StatusCode),
+		Provider:   Claude,
+		StatusCode: resp.StatusCode,
+		RetryAfter: parseRetryAfter(resp.Header),
+		Message:    strings.TrimSpace(string(body)),
+	}
+
+	var envelope claudeErrorResponse
+	if json.Unmarshal(body, &envelope) == nil && envelope.Error.Message != "" {
+		apiErr.Message = fmt.Sprintf("%s: %s", envelope.Error.Type, envelope.Error.Message)
+		switch {
+		case envelope.Error.Type == "overloaded_error":
+			apiErr.Kind = ErrServer
+		case strings.Contains(envelope.Error.Message, "credit balance"):
+			apiErr.Kind = ErrQuota
+		}
+	}
+	return apiErr
 }
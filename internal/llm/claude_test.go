@@ -0,0 +1,74 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/frfahim/gitstory/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newClaudeStub(t *testing.T, status int, body string, captured *claudeRequest) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/messages", r.URL.Path)
+		assert.Equal(t, "test-key", r.Header.Get("x-api-key"))
+		assert.Equal(t, claudeAPIVersion, r.Header.Get("anthropic-version"))
+		if captured != nil {
+			require.NoError(t, json.NewDecoder(r.Body).Decode(captured))
+		}
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestClaudeSummarize_Success(t *testing.T) {
+	var captured claudeRequest
+	server := newClaudeStub(t, http.StatusOK,
+		`{"content":[{"type":"text","text":"  Shipped auth v2  "}],"stop_reason":"end_turn"}`, &captured)
+
+	client, err := NewClaudeClient(ClientConfig{APIKey: "test-key", BaseURL: server.URL})
+	require.NoError(t, err)
+
+	resp, err := client.Summarize(context.Background(), &SummaryRequest{
+		Commits:  []types.CommitData{{Hash: "abc1234", Message: "Add auth"}},
+		Platform: Twitter,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "Shipped auth v2", resp.Summary)
+	assert.Equal(t, Twitter, resp.Platform)
+	assert.Equal(t, getDefaultModel(Claude), captured.Model)
+	assert.Equal(t, getSystemPrompt(Twitter), captured.System)
+	assert.Equal(t, getMaxTokensForPlatform(Twitter), captured.MaxTokens)
+	require.Len(t, captured.Messages, 1)
+	assert.Equal(t, "user", captured.Messages[0].Role)
+	assert.Contains(t, captured.Messages[0].Content, "Add auth")
+}
+
+func TestClaudeSummarize_APIError(t *testing.T) {
+	server := newClaudeStub(t, http.StatusUnauthorized,
+		`{"type":"error","error":{"type":"authentication_error","message":"invalid x-api-key"}}`, nil)
+
+	client, err := NewClaudeClient(ClientConfig{APIKey: "test-key", BaseURL: server.URL})
+	require.NoError(t, err)
+
+	_, err = client.Summarize(context.Background(), &SummaryRequest{Platform: Technical})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid x-api-key")
+}
+
+func TestNewClient_ClaudeAnthropicKeyAlias(t *testing.T) {
+	t.Setenv("CLAUDE_API_KEY", "")
+	t.Setenv("ANTHROPIC_API_KEY", "alias-key")
+
+	client, err := NewClient(ClientConfig{Provider: Claude})
+	require.NoError(t, err)
+	assert.Equal(t, Claude, client.GetProvider())
+	assert.Contains(t, DetectAvailableProviders(), Claude)
+}
@@ -13,13 +13,16 @@ func NewClient(config ClientConfig) (Client, error) {
 		config.APIKey = getAPIKeyFromEnv(config.Provider)
 	}
 
-	if config.APIKey == "" {
+	// Local endpoints don't need a key
+	if config.APIKey == "" && !isLocalProvider(config.Provider) {
 		return nil, fmt.Errorf("API key not provided for %s. Set %s environment variable",
 			config.Provider, getEnvKeyName(config.Provider))
 	}
 
-	if config.Model == "" {
-		config.Model = getDefaultModel(config.Provider)
+	config.Model = ResolveModel(config)
+
+	if err := validateGenerationConfig(config); err != nil {
+		return nil, err
 	}
 
 	// Create provider-specific client
@@ -29,19 +32,26 @@ func NewClient(config ClientConfig) (Client, error) {
 	case Gemini:
 		return NewGeminiClient(config)
 	case Claude:
-		return nil, fmt.Errorf("Claude provider not yet implemented")
+		return NewClaudeClient(config)
+	case Ollama, OpenAICompatible:
+		return NewLocalClient(config)
 	default:
 		return nil, fmt.Errorf("unsupported LLM provider: %s", config.Provider)
 	}
 }
 
-// DetectAvailableProviders checks which providers have API keys configured
+// DetectAvailableProviders checks which providers have API keys (or, for
+// local providers, an endpoint) configured
 func DetectAvailableProviders() []Provider {
 	var available []Provider
 
-	providers := []Provider{OpenAI, Gemini, Claude}
-
-	for _, provider := range providers {
+	for _, provider := range GetSupportedProviders() {
+		if isLocalProvider(provider) {
+			if strings.TrimSpace(os.Getenv(baseURLEnvVars[provider])) != "" {
+				available = append(available, provider)
+			}
+			continue
+		}
 		if getAPIKeyFromEnv(provider) != "" {
 			available = append(available, provider)
 		}
@@ -61,42 +71,99 @@ func GetDefaultProvider() (Provider, error) {
 	return available[0], nil
 }
 
+// apiKeyEnvVars lists the environment variables checked for each provider, in priority order
+var apiKeyEnvVars = map[Provider][]string{
+	OpenAI:           {"OPENAI_API_KEY"},
+	Claude:           {"CLAUDE_API_KEY", "ANTHROPIC_API_KEY"},
+	Gemini:           {"GEMINI_API_KEY"},
+	OpenAICompatible: {"OPENAI_COMPATIBLE_API_KEY"},
+}
+
+// modelEnvVars lists the environment variables that override each provider's model, in priority order
+var modelEnvVars = map[Provider][]string{
+	OpenAI:           {"OPENAI_MODEL"},
+	Claude:           {"CLAUDE_MODEL", "ANTHROPIC_MODEL"},
+	Gemini:           {"GEMINI_MODEL"},
+	Ollama:           {"OLLAMA_MODEL"},
+	OpenAICompatible: {"OPENAI_COMPATIBLE_MODEL"},
+}
+
 // getAPIKeyFromEnv retrieves API key from environment variables
 func getAPIKeyFromEnv(provider Provider) string {
-	envKeys := map[Provider]string{
-		OpenAI: "OPENAI_API_KEY",
-		Claude: "CLAUDE_API_KEY",
-		Gemini: "GEMINI_API_KEY",
+	for _, envKey := range apiKeyEnvVars[provider] {
+		if key := strings.TrimSpace(os.Getenv(envKey)); key != "" {
+			return key
+		}
 	}
+	return ""
+}
+
+// getEnvKeyName returns the environment variable name(s) for a provider
+func getEnvKeyName(provider Provider) string {
+	return strings.Join(apiKeyEnvVars[provider], " or ")
+}
 
-	if envKey, exists := envKeys[provider]; exists {
-		return strings.TrimSpace(os.Getenv(envKey))
+// getModelFromEnv retrieves the model override from environment variables
+func getModelFromEnv(provider Provider) string {
+	for _, envKey := range modelEnvVars[provider] {
+		if model := strings.TrimSpace(os.Getenv(envKey)); model != "" {
+			return model
+		}
 	}
 	return ""
 }
 
-// getEnvKeyName returns the environment variable name for a provider
-func getEnvKeyName(provider Provider) string {
-	envKeys := map[Provider]string{
-		OpenAI: "OPENAI_API_KEY",
-		Claude: "CLAUDE_API_KEY",
-		Gemini: "GEMINI_API_KEY",
+// ResolveModel returns the model a client will use: the configured model,
+// then the provider's model environment variable, then its default
+func ResolveModel(config ClientConfig) string {
+	if config.Model != "" {
+		return config.Model
+	}
+	if model := getModelFromEnv(config.Provider); model != "" {
+		return model
 	}
-	return envKeys[provider]
+	return getDefaultModel(config.Provider)
 }
 
+// getDefaultModel is the single source of default models; the provider constructors defer to it
 func getDefaultModel(provider Provider) string {
 	defaultModels := map[Provider]string{
 		OpenAI: "gpt-4o",
-		Claude: "claude-3",
+		Claude: "claude-sonnet-4-5",
 		Gemini: "gemini-2.5-flash-lite",
+		Ollama: "llama3.2",
 	}
 	return defaultModels[provider]
 }
 
+// maxTemperature returns the highest temperature a provider accepts
+func maxTemperature(provider Provider) float64 {
+	if provider == Claude {
+		return 1.0
+	}
+	return 2.0
+}
+
+// validateGenerationConfig checks temperature and token overrides before any request is made
+func validateGenerationConfig(config ClientConfig) error {
+	if config.Temperature != nil {
+		limit := maxTemperature(config.Provider)
+		if t := *config.Temperature; t < 0 || t > limit {
+			return fmt.Errorf("temperature %.2f out of range for %s (0-%.1f)", t, config.Provider, limit)
+		}
+	}
+	if config.MaxTokens < 0 {
+		return fmt.Errorf("max tokens must be positive, got %d", config.MaxTokens)
+	}
+	if config.ContextWindow < 0 {
+		return fmt.Errorf("context window must be positive, got %d", config.ContextWindow)
+	}
+	return nil
+}
+
 // GetSupportedProviders returns list of all supported LLM providers
 func GetSupportedProviders() []Provider {
-	return []Provider{OpenAI, Gemini, Claude}
+	return []Provider{OpenAI, Gemini, Claude, Ollama, OpenAICompatible}
 }
 
 // GetSupportedPlatforms returns list of supported output platforms
@@ -0,0 +1,71 @@
+package llm
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewClient_ModelFromEnv(t *testing.T) {
+	t.Setenv("CLAUDE_API_KEY", "test-key")
+	t.Setenv("CLAUDE_MODEL", "")
+	t.Setenv("ANTHROPIC_MODEL", "claude-from-env")
+
+	client, err := NewClient(ClientConfig{Provider: Claude})
+	require.NoError(t, err)
+	assert.Equal(t, "claude-from-env", client.(*ClaudeClient).config.Model)
+
+	// An explicit model wins over the environment
+	client, err = NewClient(ClientConfig{Provider: Claude, Model: "claude-explicit"})
+	require.NoError(t, err)
+	assert.Equal(t, "claude-explicit", client.(*ClaudeClient).config.Model)
+}
+
+func TestNewClient_DefaultModelsAgree(t *testing.T) {
+	for _, env := range []string{"OPENAI_MODEL", "GEMINI_MODEL"} {
+		t.Setenv(env, "")
+	}
+
+	openai, err := NewOpenAIClient(ClientConfig{Provider: OpenAI, APIKey: "k"})
+	require.NoError(t, err)
+	assert.Equal(t, getDefaultModel(OpenAI), openai.(*OpenAIClient).config.Model)
+
+	gemini, err := NewGeminiClient(ClientConfig{Provider: Gemini, APIKey: "k"})
+	require.NoError(t, err)
+	assert.Equal(t, getDefaultModel(Gemini), gemini.config.Model)
+}
+
+func TestNewClient_TemperatureRange(t *testing.T) {
+	t.Setenv("CLAUDE_API_KEY", "test-key")
+
+	tooHot := 1.5
+	_, err := NewClient(ClientConfig{Provider: Claude, Temperature: &tooHot})
+	assert.Error(t, err)
+
+	_, err = NewClient(ClientConfig{Provider: Claude, MaxTokens: -1})
+	assert.Error(t, err)
+}
+
+func TestClaudeSummarize_GenerationOverrides(t *testing.T) {
+	var captured claudeRequest
+	server := newClaudeStub(t, http.StatusOK, `{"content":[{"type":"text","text":"ok"}]}`, &captured)
+
+	temperature := 0.0
+	client, err := NewClaudeClient(ClientConfig{
+		APIKey:      "test-key",
+		BaseURL:     server.URL,
+		Model:       "claude-custom",
+		Temperature: &temperature,
+		MaxTokens:   2048,
+	})
+	require.NoError(t, err)
+
+	_, err = client.Summarize(context.Background(), &SummaryRequest{Platform: Blog})
+	require.NoError(t, err)
+	assert.Equal(t, "claude-custom", captured.Model)
+	assert.Equal(t, 0.0, captured.Temperature)
+	assert.Equal(t, 2048, captured.MaxTokens)
+}
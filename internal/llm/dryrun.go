@@ -0,0 +1,50 @@
+package llm
+
+// RenderedPrompt is what a provider would be sent for a request, with
+// estimates of its size and cost. It is built without contacting the provider.
+type RenderedPrompt struct {
+	Provider Provider `json:"provider"`
+	Model    string   `json:"model"`
+	// System is empty for providers that take a single combined prompt
+	System string `json:"system,omitempty"`
+	User   string `json:"user"`
+
+	InputTokens     int `json:"estimated_input_tokens"`
+	MaxOutputTokens int `json:"max_output_tokens"`
+	ContextWindow   int `json:"context_window"`
+	// Batches is the number of intermediate summaries needed when the prompt
+	// doesn't fit the context window; 0 when it is sent as is
+	Batches int `json:"batches,omitempty"`
+	// EstimatedCost is the upper bound in USD, assuming the full output limit
+	// is used; nil when the model's price is unknown
+	EstimatedCost *float64 `json:"estimated_cost_usd,omitempty"`
+}
+
+// RenderPrompt builds the prompt the provider in config would send for request.
+// No API key is needed.
+func RenderPrompt(config ClientConfig, request *SummaryRequest) *RenderedPrompt {
+	budget := NewMapReduceClient(nil, config)
+	system, user := providerPrompt(budget.config.Provider, request)
+
+	rendered := &RenderedPrompt{
+		Provider:        budget.config.Provider,
+		Model:           budget.config.Model,
+		System:          system,
+		User:            user,
+		InputTokens:     budget.promptTokens(request),
+		MaxOutputTokens: budget.config.maxTokens(request.Platform),
+		ContextWindow:   budget.contextWindow(),
+	}
+	inputTokens, outputTokens := rendered.InputTokens, rendered.MaxOutputTokens
+	if rendered.InputTokens > budget.promptBudget(request.Platform) {
+		rendered.Batches = len(budget.batchCommits(request))
+		// Each batch writes notes that the final request then reads back
+		notes := rendered.Batches * budget.config.maxTokens(Batch)
+		inputTokens += notes
+		outputTokens += notes
+	}
+	if cost, ok := EstimateCost(rendered.Provider, rendered.Model, inputTokens, outputTokens); ok {
+		rendered.EstimatedCost = &cost
+	}
+	return rendered
+}
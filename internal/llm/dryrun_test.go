@@ -0,0 +1,41 @@
+package llm
+
+import (
+	"testing"
+
+	"github.com/frfahim/gitstory/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderPrompt(t *testing.T) {
+	t.Setenv("OPENAI_MODEL", "")
+	t.Setenv("GEMINI_MODEL", "")
+	request := &SummaryRequest{
+		Commits:  []types.CommitData{{Message: "Add dry run", Author: "Alice"}},
+		Platform: Blog,
+	}
+
+	openai := RenderPrompt(ClientConfig{Provider: OpenAI}, request)
+	assert.Equal(t, "gpt-4o", openai.Model)
+	assert.Equal(t, getSystemPrompt(Blog), openai.System)
+	assert.Contains(t, openai.User, "Add dry run")
+	assert.Contains(t, openai.User, openai.System, "OpenAI repeats the system prompt in the user message")
+	assert.Equal(t, getMaxTokensForPlatform(Blog), openai.MaxOutputTokens)
+	assert.Greater(t, openai.InputTokens, 0)
+	assert.Zero(t, openai.Batches)
+	require.NotNil(t, openai.EstimatedCost)
+	assert.Greater(t, *openai.EstimatedCost, 0.0)
+
+	gemini := RenderPrompt(ClientConfig{Provider: Gemini}, request)
+	assert.Empty(t, gemini.System, "Gemini takes a single combined prompt")
+	assert.Contains(t, gemini.User, "--- TASK ---")
+
+	unknown := RenderPrompt(ClientConfig{Provider: OpenAI, Model: "in-house-model"}, request)
+	assert.Nil(t, unknown.EstimatedCost)
+
+	local := RenderPrompt(ClientConfig{Provider: Ollama, ContextWindow: 1_000}, &SummaryRequest{Commits: largeCommits(20), Platform: Blog})
+	require.NotNil(t, local.EstimatedCost)
+	assert.Zero(t, *local.EstimatedCost)
+	assert.Greater(t, local.Batches, 1)
+}
@@ -0,0 +1,210 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/openai/openai-go"
+	"google.golang.org/genai"
+)
+
+// ErrorKind classifies provider failures so callers can decide whether to
+// retry, fall back to another provider or tell the user what to fix
+type ErrorKind string
+
+const (
+	ErrAuth          ErrorKind = "auth"
+	ErrQuota         ErrorKind = "quota"
+	ErrRateLimit     ErrorKind = "rate-limit"
+	ErrContentFilter ErrorKind = "content-filter"
+	ErrNetwork       ErrorKind = "network"
+	ErrServer        ErrorKind = "server"
+	ErrBadRequest    ErrorKind = "bad-request"
+)
+
+// Error is a classified provider error. The SDK error is kept in Err.
+type Error struct {
+	Kind       ErrorKind
+	Provider   Provider
+	StatusCode int
+	// RetryAfter is the delay the provider asked for, zero when it didn't say
+	RetryAfter time.Duration
+	Message    string
+	Err        error
+}
+
+func (e *Error) Error() string {
+	msg := e.Message
+	if msg == "" && e.Err != nil {
+		msg = e.Err.Error()
+	}
+	if e.StatusCode != 0 {
+		return fmt.Sprintf("%s %s error (%d): %s", e.Provider, e.Kind, e.StatusCode, msg)
+	}
+	return fmt.Sprintf("%s %s error: %s", e.Provider, e.Kind, msg)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Retryable reports whether the same request may succeed if sent again
+func (e *Error) Retryable() bool {
+	switch e.Kind {
+	case ErrRateLimit, ErrNetwork, ErrServer:
+		return true
+	}
+	return false
+}
+
+// Hint returns a short, actionable suggestion for the user
+func (e *Error) Hint() string {
+	switch e.Kind {
+	case ErrAuth:
+		if envVars := getEnvKeyName(e.Provider); envVars != "" {
+			return fmt.Sprintf("check the API key in %s", envVars)
+		}
+		return "check the provider credentials"
+	case ErrQuota:
+		return fmt.Sprintf("the %s account is out of quota or credit; check billing or use another --provider", e.Provider)
+	case ErrRateLimit:
+		return "the provider is rate limiting requests; wait a moment, raise --max-retries or use another --provider"
+	case ErrContentFilter:
+		return "the provider's safety filter blocked the request; try fewer commits, another --path or another --provider"
+	case ErrNetwork:
+		return "could not reach the provider; check your connection, proxy or --timeout"
+	case ErrServer:
+		return "the provider is having problems; try again later or use another --provider"
+	case ErrBadRequest:
+		return "the provider rejected the request; check --model and --max-tokens"
+	}
+	return ""
+}
+
+// kindForStatus maps an HTTP status code to an error kind
+func kindForStatus(status int) ErrorKind {
+	switch {
+	case status == http.StatusUnauthorized || status == http.StatusForbidden:
+		return ErrAuth
+	case status == http.StatusPaymentRequired:
+		return ErrQuota
+	case status == http.StatusTooManyRequests:
+		return ErrRateLimit
+	case status == http.StatusRequestTimeout:
+		return ErrNetwork
+	case status >= 500:
+		return ErrServer
+	}
+	return ErrBadRequest
+}
+
+// classifyError turns SDK and transport errors into *Error. Errors that are
+// already classified, or that can't be, are returned unchanged.
+func classifyError(provider Provider, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var llmErr *Error
+	if errors.As(err, &llmErr) {
+		return err
+	}
+
+	var openaiErr *openai.Error
+	if errors.As(err, &openaiErr) {
+		classified := &Error{
+			Kind:       kindForStatus(openaiErr.StatusCode),
+			Provider:   provider,
+			StatusCode: openaiErr.StatusCode,
+			Message:    openaiErr.Message,
+			Err:        err,
+		}
+		switch openaiErr.Code {
+		case "insufficient_quota":
+			classified.Kind = ErrQuota
+		case "content_filter", "content_policy_violation":
+			classified.Kind = ErrContentFilter
+		}
+		if openaiErr.Response != nil {
+			classified.RetryAfter = parseRetryAfter(openaiErr.Response.Header)
+		}
+		return classified
+	}
+
+	var geminiErr genai.APIError
+	if errors.As(err, &geminiErr) {
+		classified := &Error{
+			Kind:       kindForStatus(geminiErr.Code),
+			Provider:   provider,
+			StatusCode: geminiErr.Code,
+			Message:    geminiErr.Message,
+			RetryAfter: geminiRetryDelay(geminiErr.Details),
+			Err:        err,
+		}
+		// Gemini reports a bad key as 400 INVALID_ARGUMENT
+		if geminiErr.Code == http.StatusBadRequest && strings.Contains(strings.ToLower(geminiErr.Message), "api key") {
+			classified.Kind = ErrAuth
+		}
+		return classified
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return &Error{Kind: ErrNetwork, Provider: provider, Message: "request timed out", Err: err}
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return &Error{Kind: ErrNetwork, Provider: provider, Err: err}
+	}
+
+	return err
+}
+
+// contentFilterError reports a response that was withheld by the provider's safety filter
+func contentFilterError(provider Provider, reason string) *Error {
+	return &Error{
+		Kind:     ErrContentFilter,
+		Provider: provider,
+		Message:  fmt.Sprintf("response blocked (%s)", reason),
+	}
+}
+
+// parseRetryAfter reads retry-after-ms or Retry-After (seconds or HTTP date)
+func parseRetryAfter(header http.Header) time.Duration {
+	if ms, err := strconv.ParseFloat(header.Get("retry-after-ms"), 64); err == nil && ms > 0 {
+		return time.Duration(ms * float64(time.Millisecond))
+	}
+	value := strings.TrimSpace(header.Get("Retry-After"))
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.ParseFloat(value, 64); err == nil && seconds > 0 {
+		return time.Duration(seconds * float64(time.Second))
+	}
+	if when, err := http.ParseTime(value); err == nil {
+		if delay := time.Until(when); delay > 0 {
+			return delay
+		}
+	}
+	return 0
+}
+
+// geminiRetryDelay reads the google.rpc.RetryInfo detail Gemini attaches to 429s
+func geminiRetryDelay(details []map[string]any) time.Duration {
+	for _, detail := range details {
+		if !strings.HasSuffix(fmt.Sprint(detail["@type"]), "google.rpc.RetryInfo") {
+			continue
+		}
+		if delay, ok := detail["retryDelay"].(string); ok {
+			if d, err := time.ParseDuration(delay); err == nil {
+				return d
+			}
+		}
+	}
+	return 0
+}
@@ -0,0 +1,91 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genai"
+)
+
+func TestClassifyError_OpenAI(t *testing.T) {
+	tests := []struct {
+		status int
+		body   string
+		kind   ErrorKind
+	}{
+		{http.StatusUnauthorized, `{"error":{"message":"bad key","type":"invalid_request_error","code":"invalid_api_key"}}`, ErrAuth},
+		{http.StatusTooManyRequests, `{"error":{"message":"slow down","type":"requests","code":"rate_limit_exceeded"}}`, ErrRateLimit},
+		{http.StatusTooManyRequests, `{"error":{"message":"no credit","type":"insufficient_quota","code":"insufficient_quota"}}`, ErrQuota},
+		{http.StatusServiceUnavailable, `{"error":{"message":"down","type":"server_error"}}`, ErrServer},
+	}
+	for _, tt := range tests {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("Retry-After", "7")
+			w.WriteHeader(tt.status)
+			w.Write([]byte(tt.body))
+		}))
+
+		client, err := NewOpenAIClient(ClientConfig{Provider: OpenAI, APIKey: "sk-test", BaseURL: server.URL})
+		require.NoError(t, err)
+		_, err = client.Summarize(context.Background(), &SummaryRequest{Platform: Note})
+		server.Close()
+
+		var llmErr *Error
+		require.ErrorAs(t, err, &llmErr, "status %d", tt.status)
+		assert.Equal(t, tt.kind, llmErr.Kind, "status %d", tt.status)
+		assert.Equal(t, OpenAI, llmErr.Provider)
+		assert.Equal(t, 7*time.Second, llmErr.RetryAfter)
+	}
+}
+
+func TestClassifyError_Gemini(t *testing.T) {
+	err := classifyError(Gemini, fmt.Errorf("wrapped: %w", genai.APIError{
+		Code:    http.StatusTooManyRequests,
+		Message: "Resource has been exhausted",
+		Status:  "RESOURCE_EXHAUSTED",
+		Details: []map[string]any{{"@type": "type.googleapis.com/google.rpc.RetryInfo", "retryDelay": "31s"}},
+	}))
+
+	var llmErr *Error
+	require.ErrorAs(t, err, &llmErr)
+	assert.Equal(t, ErrRateLimit, llmErr.Kind)
+	assert.Equal(t, 31*time.Second, llmErr.RetryAfter)
+	assert.True(t, llmErr.Retryable())
+
+	err = classifyError(Gemini, genai.APIError{Code: http.StatusBadRequest, Message: "API key not valid. Please pass a valid API key."})
+	require.ErrorAs(t, err, &llmErr)
+	assert.Equal(t, ErrAuth, llmErr.Kind)
+}
+
+func TestClassifyError_Claude(t *testing.T) {
+	server := newClaudeStub(t, http.StatusTooManyRequests, `{"type":"error","error":{"type":"rate_limit_error","message":"Number of requests has exceeded your rate limit"}}`, nil)
+	client, err := NewClaudeClient(ClientConfig{Provider: Claude, APIKey: "test-key", BaseURL: server.URL})
+	require.NoError(t, err)
+
+	_, err = client.Summarize(context.Background(), &SummaryRequest{Platform: Note})
+	var llmErr *Error
+	require.ErrorAs(t, err, &llmErr)
+	assert.Equal(t, ErrRateLimit, llmErr.Kind)
+	assert.Equal(t, http.StatusTooManyRequests, llmErr.StatusCode)
+}
+
+func TestClassifyError_Network(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close() // nothing listens on the address any more
+
+	client, err := NewClaudeClient(ClientConfig{Provider: Claude, APIKey: "test-key", BaseURL: server.URL})
+	require.NoError(t, err)
+
+	_, err = client.Summarize(context.Background(), &SummaryRequest{Platform: Note})
+	var llmErr *Error
+	require.ErrorAs(t, err, &llmErr)
+	assert.Equal(t, ErrNetwork, llmErr.Kind)
+	assert.True(t, llmErr.Retryable())
+}
@@ -0,0 +1,104 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// FallbackClient tries an ordered chain of clients, moving on to the next
+// provider when one fails with an auth, quota, rate-limit or outage error.
+// The provider that answered is recorded in SummaryResponse.Provider.
+type FallbackClient struct {
+	clients []Client
+	// OnFallback, if set, is called when a provider fails and the next one is tried
+	OnFallback func(failed Provider, err error, next Provider)
+}
+
+// NewFallbackClient creates a client that tries clients in order
+func NewFallbackClient(clients ...Client) (*FallbackClient, error) {
+	if len(clients) == 0 {
+		return nil, fmt.Errorf("fallback chain needs at least one provider")
+	}
+	return &FallbackClient{clients: clients}, nil
+}
+
+// GetProvider returns the first provider in the chain
+func (c *FallbackClient) GetProvider() Provider {
+	return c.clients[0].GetProvider()
+}
+
+// Providers returns the providers in the chain, in order
+func (c *FallbackClient) Providers() []Provider {
+	providers := make([]Provider, len(c.clients))
+	for i, client := range c.clients {
+		providers[i] = client.GetProvider()
+	}
+	return providers
+}
+
+func (c *FallbackClient) Summarize(ctx context.Context, request *SummaryRequest) (*SummaryResponse, error) {
+	return c.do(ctx, func(client Client) (*SummaryResponse, bool, error) {
+		response, err := client.Summarize(ctx, request)
+		return response, true, err
+	})
+}
+
+// SummarizeStream streams from the first provider that answers. Once a
+// provider has delivered text, its failure is returned instead of falling back.
+func (c *FallbackClient) SummarizeStream(ctx context.Context, request *SummaryRequest, onDelta func(string)) (*SummaryResponse, error) {
+	return c.do(ctx, func(client Client) (*SummaryResponse, bool, error) {
+		streamed := false
+		response, err := SummarizeStream(ctx, client, request, func(delta string) {
+			streamed = true
+			onDelta(delta)
+		})
+		return response, !streamed, err
+	})
+}
+
+func (c *FallbackClient) do(ctx context.Context, call func(client Client) (*SummaryResponse, bool, error)) (*SummaryResponse, error) {
+	var errs []error
+	for i, client := range c.clients {
+		response, canFallback, err := call(client)
+		if err == nil {
+			if response.Provider == "" {
+				response.Provider = client.GetProvider()
+			}
+			return response, nil
+		}
+
+		errs = append(errs, err)
+		if i == len(c.clients)-1 || !canFallback || ctx.Err() != nil || !shouldFallback(err) {
+			break
+		}
+		if c.OnFallback != nil {
+			c.OnFallback(client.GetProvider(), err, c.clients[i+1].GetProvider())
+		}
+	}
+
+	last := errs[len(errs)-1]
+	if len(errs) == 1 {
+		return nil, last
+	}
+	// Wrap only the last error so callers report (and hint at) the final failure
+	earlier := make([]string, len(errs)-1)
+	for i, err := range errs[:len(errs)-1] {
+		earlier[i] = err.Error()
+	}
+	return nil, fmt.Errorf("%w (after: %s)", last, strings.Join(earlier, "; "))
+}
+
+// shouldFallback reports whether another provider might succeed where this one failed
+func shouldFallback(err error) bool {
+	var llmErr *Error
+	if !errors.As(err, &llmErr) {
+		return false
+	}
+	switch llmErr.Kind {
+	case ErrAuth, ErrQuota, ErrRateLimit, ErrNetwork, ErrServer:
+		return true
+	}
+	return false
+}
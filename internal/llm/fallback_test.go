@@ -0,0 +1,67 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// providerClient is a scriptedClient reporting a given provider
+type providerClient struct {
+	scriptedClient
+	provider Provider
+}
+
+func (c *providerClient) GetProvider() Provider { return c.provider }
+
+func TestFallbackClient_UsesNextProvider(t *testing.T) {
+	openai := &providerClient{scriptedClient{errs: []error{&Error{Kind: ErrQuota, Provider: OpenAI}}}, OpenAI}
+	gemini := &providerClient{provider: Gemini}
+	ollama := &providerClient{provider: Ollama}
+
+	client, err := NewFallbackClient(openai, gemini, ollama)
+	require.NoError(t, err)
+	var fellBack []Provider
+	client.OnFallback = func(failed Provider, err error, next Provider) {
+		fellBack = append(fellBack, failed, next)
+	}
+
+	resp, err := client.Summarize(context.Background(), &SummaryRequest{Platform: Note})
+	require.NoError(t, err)
+	assert.Equal(t, Gemini, resp.Provider)
+	assert.Equal(t, []Provider{OpenAI, Gemini}, fellBack)
+	assert.Equal(t, 0, ollama.calls)
+	assert.Equal(t, []Provider{OpenAI, Gemini, Ollama}, client.Providers())
+}
+
+func TestFallbackClient_StopsOnRequestErrors(t *testing.T) {
+	for _, failure := range []error{
+		&Error{Kind: ErrContentFilter, Provider: OpenAI},
+		errors.New("unclassified"),
+	} {
+		openai := &providerClient{scriptedClient{errs: []error{failure}}, OpenAI}
+		gemini := &providerClient{provider: Gemini}
+		client, err := NewFallbackClient(openai, gemini)
+		require.NoError(t, err)
+
+		_, err = client.Summarize(context.Background(), &SummaryRequest{})
+		assert.Equal(t, failure, err)
+		assert.Equal(t, 0, gemini.calls)
+	}
+}
+
+func TestFallbackClient_AllFail(t *testing.T) {
+	openai := &providerClient{scriptedClient{errs: []error{&Error{Kind: ErrAuth, Provider: OpenAI}}}, OpenAI}
+	gemini := &providerClient{scriptedClient{errs: []error{&Error{Kind: ErrServer, Provider: Gemini}}}, Gemini}
+	client, err := NewFallbackClient(openai, gemini)
+	require.NoError(t, err)
+
+	_, err = client.Summarize(context.Background(), &SummaryRequest{})
+	var llmErr *Error
+	require.ErrorAs(t, err, &llmErr)
+	assert.Equal(t, Gemini, llmErr.Provider, "the last provider's error is reported")
+	assert.Contains(t, err.Error(), "openai auth error")
+}
@@ -3,6 +3,7 @@ package llm
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"google.golang.org/genai"
 )
@@ -21,7 +22,7 @@ func NewGeminiClient(config ClientConfig) (*GeminiClient, error) {
 		return nil, fmt.Errorf("API key is required for Gemini client")
 	}
 	if config.Model == "" {
-		config.Model = "gemini-2.5-flash-lite"
+		config.Model = getDefaultModel(Gemini)
 	}
 	ctx := context.Background()
 	client, err := genai.NewClient(ctx, &genai.ClientConfig{
@@ -54,20 +55,25 @@ func (c *GeminiClient) ValidateCredentials(ctx context.Context) error {
 	return nil
 }
 
-func (c *GeminiClient) Summarize(ctx context.Context, request *SummaryRequest) (*SummaryResponse, error) {
-	systemPrompt := getSystemPrompt(request.Platform)
-	userPrompt := buildPrompt(request)
-	// For Gemini, we need to combine system and user prompts since it doesn't have separate system messages
-	// We'll structure it as: "You are X. Here's the task: Y"
-	fullPrompt := fmt.Sprintf("%s\n\n--- TASK ---\n%s", systemPrompt, userPrompt)
+// generateParams builds the prompt and generation config shared by Summarize and SummarizeStream
+func (c *GeminiClient) generateParams(request *SummaryRequest) ([]*genai.Content, *genai.GenerateContentConfig) {
+	_, fullPrompt := providerPrompt(Gemini, request)
 
 	config := &genai.GenerateContentConfig{
-		Temperature:     genai.Ptr[float32](0.7),
-		MaxOutputTokens: int32(getMaxTokensForPlatform(request.Platform)),
+		Temperature:     genai.Ptr(float32(c.config.temperature())),
+		MaxOutputTokens: int32(c.config.maxTokens(request.Platform)),
 	}
-	result, err := c.client.Models.GenerateContent(ctx, c.config.Model, genai.Text(fullPrompt), config)
+	return genai.Text(fullPrompt), config
+}
+
+func (c *GeminiClient) Summarize(ctx context.Context, request *SummaryRequest) (*SummaryResponse, error) {
+	contents, config := c.generateParams(request)
+	result, err := c.client.Models.GenerateContent(ctx, c.config.Model, contents, config)
 	if err != nil {
-		return nil, fmt.Errorf("failed to generate content: %w", err)
+		return nil, fmt.Errorf("failed to generate content: %w", classifyError(Gemini, err))
+	}
+	if err := geminiBlocked(result); err != nil {
+		return nil, err
 	}
 	if result == nil || len(result.Candidates) == 0 {
 		return nil, fmt.Errorf("no response from Gemini API")
@@ -76,5 +82,51 @@ func (c *GeminiClient) Summarize(ctx context.Context, request *SummaryRequest) (
 	return &SummaryResponse{
 		Platform: request.Platform,
 		Summary:  result.Text(),
+		Provider: Gemini,
 	}, nil
 }
+
+// SummarizeStream streams the generation, calling onDelta with each text chunk
+func (c *GeminiClient) SummarizeStream(ctx context.Context, request *SummaryRequest, onDelta func(string)) (*SummaryResponse, error) {
+	contents, config := c.generateParams(request)
+
+	var summary strings.Builder
+	for result, err := range c.client.Models.GenerateContentStream(ctx, c.config.Model, contents, config) {
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate content: %w", classifyError(Gemini, err))
+		}
+		if err := geminiBlocked(result); err != nil {
+			return nil, err
+		}
+		if delta := result.Text(); delta != "" {
+			summary.WriteString(delta)
+			onDelta(delta)
+		}
+	}
+	if summary.Len() == 0 {
+		return nil, fmt.Errorf("no response from Gemini API")
+	}
+
+	return &SummaryResponse{
+		Platform: request.Platform,
+		Summary:  summary.String(),
+		Provider: Gemini,
+	}, nil
+}
+
+// geminiBlocked reports a prompt or candidate stopped by Gemini's safety filters
+func geminiBlocked(result *genai.GenerateContentResponse) error {
+	if result == nil {
+		return nil
+	}
+	if result.PromptFeedback != nil && result.PromptFeedback.BlockReason != "" {
+		return contentFilterError(Gemini, string(result.PromptFeedback.BlockReason))
+	}
+	for _, candidate := range result.Candidates {
+		switch candidate.FinishReason {
+		case genai.FinishReasonSafety, genai.FinishReasonProhibitedContent, genai.FinishReasonBlocklist, genai.FinishReasonSPII:
+			return contentFilterError(Gemini, string(candidate.FinishReason))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package llm
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/openai/openai-go"
+	"github.com/openai/openai-go/option"
+)
+
+const (
+	defaultOllamaHost = "http://127.0.0.1:11434"
+	defaultOllamaPort = "11434"
+)
+
+// LocalClient implements the Client interface for self-hosted endpoints that
+// speak the OpenAI chat completions protocol (Ollama, vLLM, LM Studio, ...).
+// Prompts never leave the configured endpoint.
+type LocalClient struct {
+	*OpenAIClient
+	provider Provider
+}
+
+// GetProvider returns the provider type
+func (c *LocalClient) GetProvider() Provider {
+	return c.provider
+}
+
+// NewLocalClient creates a client for a local or self-hosted endpoint.
+// No API key is required; if one is configured it is sent as a bearer token.
+func NewLocalClient(config ClientConfig) (*LocalClient, error) {
+	if config.BaseURL == "" {
+		config.BaseURL = getBaseURLFromEnv(config.Provider)
+	} else if config.Provider == Ollama {
+		config.BaseURL = ollamaBaseURL(config.BaseURL)
+	}
+	if config.BaseURL == "" {
+		return nil, fmt.Errorf("base URL is required for %s. Set %s environment variable",
+			config.Provider, baseURLEnvVars[config.Provider])
+	}
+	config.Model = ResolveModel(config)
+	if config.Model == "" {
+		return nil, fmt.Errorf("model is required for %s. Set %s environment variable or use --model",
+			config.Provider, strings.Join(modelEnvVars[config.Provider], " or "))
+	}
+
+	opts := []option.RequestOption{
+		option.WithBaseURL(config.BaseURL),
+		// Always override the key so OPENAI_API_KEY from the environment is never sent to a local endpoint
+		option.WithAPIKey(config.APIKey),
+		option.WithMaxRetries(0),
+	}
+	if config.APIKey == "" {
+		opts = append(opts, option.WithHeaderDel("authorization"))
+	}
+	client := openai.NewClient(opts...)
+
+	return &LocalClient{
+		OpenAIClient: &OpenAIClient{
+			client: &client,
+			config: config,
+		},
+		provider: config.Provider,
+	}, nil
+}
+
+// baseURLEnvVars maps local providers to the environment variable holding their endpoint
+var baseURLEnvVars = map[Provider]string{
+	Ollama:           "OLLAMA_HOST",
+	OpenAICompatible: "OPENAI_COMPATIBLE_BASE_URL",
+}
+
+// isLocalProvider reports whether the provider talks to a self-hosted endpoint
+func isLocalProvider(provider Provider) bool {
+	_, ok := baseURLEnvVars[provider]
+	return ok
+}
+
+// getBaseURLFromEnv returns the OpenAI-compatible base URL for a local provider
+func getBaseURLFromEnv(provider Provider) string {
+	value := strings.TrimSpace(os.Getenv(baseURLEnvVars[provider]))
+	if provider == Ollama {
+		if value == "" {
+			value = defaultOllamaHost
+		}
+		return ollamaBaseURL(value)
+	}
+	return value
+}
+
+// ollamaBaseURL turns an OLLAMA_HOST value ("localhost", "gpu-box:8080",
+// "http://gpu-box", "0.0.0.0") into the URL of Ollama's OpenAI-compatible API.
+// As in Ollama, a host without a scheme defaults to port 11434 and one with
+// a scheme to the scheme's port.
+func ollamaBaseURL(host string) string {
+	host = strings.TrimSpace(host)
+	if host == "" {
+		return ""
+	}
+	if !strings.Contains(host, "://") {
+		host = "http://" + host
+		if u, err := url.Parse(host); err == nil && u.Port() == "" {
+			u.Host = net.JoinHostPort(u.Hostname(), defaultOllamaPort)
+			host = u.String()
+		}
+	}
+	host = strings.TrimRight(host, "/")
+	// 0.0.0.0 is a listen address, not something we can connect to
+	host = strings.Replace(host, "://0.0.0.0", "://127.0.0.1", 1)
+	if !strings.HasSuffix(host, "/v1") {
+		host += "/v1"
+	}
+	return host
+}
@@ -0,0 +1,76 @@
+package llm
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOllamaBaseURL(t *testing.T) {
+	tests := map[string]string{
+		"":                          "",
+		"localhost:11434":           "http://localhost:11434/v1",
+		"gpu-box":                   "http://gpu-box:11434/v1",
+		"0.0.0.0":                   "http://127.0.0.1:11434/v1",
+		"[::1]":                     "http://[::1]:11434/v1",
+		"http://gpu-box":            "http://gpu-box/v1",
+		"0.0.0.0:11434":             "http://127.0.0.1:11434/v1",
+		"https://gpu-box:8443/":     "https://gpu-box:8443/v1",
+		"http://127.0.0.1:11434/v1": "http://127.0.0.1:11434/v1",
+	}
+	for host, expected := range tests {
+		assert.Equal(t, expected, ollamaBaseURL(host), "host %q", host)
+	}
+}
+
+func TestLocalClientSummarize_NoAPIKey(t *testing.T) {
+	// A hosted key in the environment must never reach the local endpoint
+	t.Setenv("OPENAI_API_KEY", "sk-hosted")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/chat/completions", r.URL.Path)
+		assert.Empty(t, r.Header.Get("Authorization"))
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id":"1","object":"chat.completion","model":"llama3.2","choices":[{"index":0,"finish_reason":"stop","message":{"role":"assistant","content":"local summary"}}]}`))
+	}))
+	defer server.Close()
+	t.Setenv("OLLAMA_HOST", server.URL)
+
+	client, err := NewClient(ClientConfig{Provider: Ollama})
+	require.NoError(t, err)
+	assert.Equal(t, Ollama, client.GetProvider())
+
+	resp, err := client.Summarize(context.Background(), &SummaryRequest{Platform: Note})
+	require.NoError(t, err)
+	assert.Equal(t, "local summary", resp.Summary)
+
+	// A configured base URL is an Ollama host too, with or without /v1
+	t.Setenv("OLLAMA_HOST", "")
+	client, err = NewClient(ClientConfig{Provider: Ollama, BaseURL: server.URL})
+	require.NoError(t, err)
+	resp, err = client.Summarize(context.Background(), &SummaryRequest{Platform: Note})
+	require.NoError(t, err)
+	assert.Equal(t, "local summary", resp.Summary)
+}
+
+func TestDetectAvailableProviders_Local(t *testing.T) {
+	for _, env := range []string{"OPENAI_API_KEY", "GEMINI_API_KEY", "CLAUDE_API_KEY", "ANTHROPIC_API_KEY", "OPENAI_COMPATIBLE_BASE_URL"} {
+		t.Setenv(env, "")
+	}
+	t.Setenv("OLLAMA_HOST", "localhost:11434")
+
+	assert.Equal(t, []Provider{Ollama}, DetectAvailableProviders())
+}
+
+func TestNewClient_OpenAICompatibleRequiresModel(t *testing.T) {
+	t.Setenv("OPENAI_COMPATIBLE_BASE_URL", "http://localhost:8000/v1")
+	t.Setenv("OPENAI_COMPATIBLE_MODEL", "")
+
+	_, err := NewClient(ClientConfig{Provider: OpenAICompatible})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "OPENAI_COMPATIBLE_MODEL")
+}
@@ -0,0 +1,196 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/frfahim/gitstory/internal/types"
+)
+
+// MapReduceClient summarizes commit sets that don't fit the model's context
+// window: commits are split into batches that fit, each batch is summarized
+// (map), and the batch summaries are combined into the final output (reduce).
+// Requests that fit are passed straight through.
+type MapReduceClient struct {
+	client Client
+	config ClientConfig
+	// OnBatch, if set, is called before each intermediate summary
+	OnBatch func(batch, batches int)
+}
+
+// NewMapReduceClient wraps client, budgeting prompts for the model in config
+func NewMapReduceClient(client Client, config ClientConfig) *MapReduceClient {
+	config.Model = ResolveModel(config)
+	return &MapReduceClient{client: client, config: config}
+}
+
+// GetProvider returns the wrapped client's provider
+func (c *MapReduceClient) GetProvider() Provider {
+	return c.client.GetProvider()
+}
+
+func (c *MapReduceClient) Summarize(ctx context.Context, request *SummaryRequest) (*SummaryResponse, error) {
+	return c.run(ctx, request, func(request *SummaryRequest) (*SummaryResponse, error) {
+		return c.client.Summarize(ctx, request)
+	})
+}
+
+// SummarizeStream streams only the final summary; intermediate batches are not shown
+func (c *MapReduceClient) SummarizeStream(ctx context.Context, request *SummaryRequest, onDelta func(string)) (*SummaryResponse, error) {
+	return c.run(ctx, request, func(request *SummaryRequest) (*SummaryResponse, error) {
+		return SummarizeStream(ctx, c.client, request, onDelta)
+	})
+}
+
+func (c *MapReduceClient) run(ctx context.Context, request *SummaryRequest, final func(*SummaryRequest) (*SummaryResponse, error)) (*SummaryResponse, error) {
+	if c.promptTokens(request) <= c.promptBudget(request.Platform) {
+		return final(request)
+	}
+
+	// Map: summarize each batch of commits
+	var partials []string
+	batches := c.batchCommits(request)
+	for i, batch := range batches {
+		if c.OnBatch != nil {
+			c.OnBatch(i+1, len(batches))
+		}
+		response, err := c.client.Summarize(ctx, &SummaryRequest{
+			Commits:     batch,
+			Platform:    Batch,
+			UserContext: request.UserContext,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to summarize batch %d of %d: %w", i+1, len(batches), err)
+		}
+		partials = append(partials, response.Summary)
+	}
+
+	// Reduce: merge the batch summaries, condensing them further while they don't fit
+	reduce := &SummaryRequest{
+		Platform:         request.Platform,
+		UserContext:      request.UserContext,
+		PartialSummaries: partials,
+	}
+	for c.promptTokens(reduce) > c.promptBudget(request.Platform) {
+		groups := c.groupPartials(reduce)
+		if len(groups) == len(reduce.PartialSummaries) {
+			return nil, fmt.Errorf("context window of %s is too small to combine batch summaries; raise --context-window or summarize fewer commits", c.config.Model)
+		}
+
+		var condensed []string
+		for i, group := range groups {
+			if c.OnBatch != nil {
+				c.OnBatch(i+1, len(groups))
+			}
+			response, err := c.client.Summarize(ctx, &SummaryRequest{
+				Platform:         Batch,
+				UserContext:      request.UserContext,
+				PartialSummaries: group,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to combine batch summaries: %w", err)
+			}
+			condensed = append(condensed, response.Summary)
+		}
+		reduce.PartialSummaries = condensed
+	}
+
+	return final(reduce)
+}
+
+// contextWindow returns the configured or known context size of the model
+func (c *MapReduceClient) contextWindow() int {
+	if c.config.ContextWindow > 0 {
+		return c.config.ContextWindow
+	}
+	return ContextWindow(c.config.Provider, c.config.Model)
+}
+
+// promptBudget returns the tokens available for a prompt on platform, after
+// reserving room for the answer and a margin for estimation error
+func (c *MapReduceClient) promptBudget(platform Platform) int {
+	return (c.contextWindow() - c.config.maxTokens(platform)) * 9 / 10
+}
+
+// promptTokens estimates the size of the messages sent for request
+func (c *MapReduceClient) promptTokens(request *SummaryRequest) int {
+	system, user := providerPrompt(c.config.Provider, request)
+	return EstimateTokens(c.config.Provider, system) + EstimateTokens(c.config.Provider, user)
+}
+
+// batchCommits packs commits, in order, into batches whose prompts fit the budget.
+// A commit too large for a batch of its own is sent without its code changes.
+func (c *MapReduceClient) batchCommits(request *SummaryRequest) [][]types.CommitData {
+	overhead := c.promptTokens(&SummaryRequest{Platform: Batch, UserContext: request.UserContext})
+	budget := c.promptBudget(Batch) - overhead
+
+	commits := make([]types.CommitData, len(request.Commits))
+	costs := make([]int, len(request.Commits))
+	for i, commit := range request.Commits {
+		costs[i] = c.commitTokens(i+1, commit)
+		if costs[i] > budget {
+			commit = withoutContent(commit)
+			costs[i] = c.commitTokens(i+1, commit)
+		}
+		commits[i] = commit
+	}
+
+	var batches [][]types.CommitData
+	for _, span := range pack(costs, budget) {
+		batches = append(batches, commits[span[0]:span[1]])
+	}
+	return batches
+}
+
+// groupPartials packs batch summaries into groups that can be condensed in one request
+func (c *MapReduceClient) groupPartials(request *SummaryRequest) [][]string {
+	overhead := c.promptTokens(&SummaryRequest{Platform: Batch, UserContext: request.UserContext, PartialSummaries: []string{""}})
+	budget := c.promptBudget(Batch) - overhead
+
+	costs := make([]int, len(request.PartialSummaries))
+	for i, partial := range request.PartialSummaries {
+		costs[i] = EstimateTokens(c.config.Provider, fmt.Sprintf("=== Batch %d ===\n%s\n\n", i+1, partial))
+	}
+
+	var groups [][]string
+	for _, span := range pack(costs, budget) {
+		groups = append(groups, request.PartialSummaries[span[0]:span[1]])
+	}
+	return groups
+}
+
+func (c *MapReduceClient) commitTokens(index int, commit types.CommitData) int {
+	var section strings.Builder
+	writeCommit(&section, index, commit)
+	return EstimateTokens(c.config.Provider, section.String())
+}
+
+// pack splits items into consecutive [start, end) spans whose costs sum to at
+// most budget; an item over budget gets a span of its own
+func pack(costs []int, budget int) [][2]int {
+	var spans [][2]int
+	start, used := 0, 0
+	for i, cost := range costs {
+		if i > start && used+cost > budget {
+			spans = append(spans, [2]int{start, i})
+			start, used = i, 0
+		}
+		used += cost
+	}
+	if start < len(costs) {
+		spans = append(spans, [2]int{start, len(costs)})
+	}
+	return spans
+}
+
+// withoutContent returns a copy of commit with the code changes dropped, keeping the file list
+func withoutContent(commit types.CommitData) types.CommitData {
+	files := make([]types.FileChange, len(commit.Files))
+	for i, file := range commit.Files {
+		file.Content = ""
+		files[i] = file
+	}
+	commit.Files = files
+	return commit
+}
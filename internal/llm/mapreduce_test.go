@@ -0,0 +1,93 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/frfahim/gitstory/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingClient records every request and answers with a short note
+type recordingClient struct {
+	requests []*SummaryRequest
+}
+
+func (c *recordingClient) GetProvider() Provider { return OpenAI }
+
+func (c *recordingClient) Summarize(ctx context.Context, request *SummaryRequest) (*SummaryResponse, error) {
+	c.requests = append(c.requests, request)
+	return &SummaryResponse{Summary: fmt.Sprintf("notes %d", len(c.requests)), Platform: request.Platform}, nil
+}
+
+func largeCommits(n int) []types.CommitData {
+	commits := make([]types.CommitData, n)
+	for i := range commits {
+		commits[i] = types.CommitData{
+			Message: fmt.Sprintf("Change %d", i),
+			Author:  "Alice",
+			Files: []types.FileChange{
+				{Path: fmt.Sprintf("pkg/file%d.go", i), Status: "modified", Content: strings.Repeat("+ line of code\n", 15)},
+			},
+		}
+	}
+	return commits
+}
+
+func TestMapReduceClient_PassesThroughSmallRequests(t *testing.T) {
+	inner := &recordingClient{}
+	client := NewMapReduceClient(inner, ClientConfig{Provider: OpenAI, Model: "gpt-4o"})
+
+	request := &SummaryRequest{Commits: largeCommits(3), Platform: Blog}
+	_, err := client.Summarize(context.Background(), request)
+	require.NoError(t, err)
+	require.Len(t, inner.requests, 1)
+	assert.Same(t, request, inner.requests[0])
+}
+
+func TestMapReduceClient_BatchesLargeRequests(t *testing.T) {
+	inner := &recordingClient{}
+	client := NewMapReduceClient(inner, ClientConfig{Provider: OpenAI, ContextWindow: 4_000})
+	var announced []int
+	client.OnBatch = func(batch, batches int) { announced = append(announced, batch) }
+
+	commits := largeCommits(60)
+	resp, err := client.Summarize(context.Background(), &SummaryRequest{Commits: commits, Platform: Technical, UserContext: "Sprint 9"})
+	require.NoError(t, err)
+
+	// Every batch but the final reduce goes out on the internal platform, in order
+	final := inner.requests[len(inner.requests)-1]
+	maps := inner.requests[:len(inner.requests)-1]
+	require.Greater(t, len(maps), 1)
+	var seen []types.CommitData
+	for _, request := range maps {
+		assert.Equal(t, Batch, request.Platform)
+		assert.Equal(t, "Sprint 9", request.UserContext)
+		assert.LessOrEqual(t, client.promptTokens(request), client.promptBudget(Batch))
+		seen = append(seen, request.Commits...)
+	}
+	assert.Equal(t, commits, seen)
+	assert.Len(t, announced, len(maps))
+
+	assert.Equal(t, Technical, final.Platform)
+	assert.Empty(t, final.Commits)
+	assert.Len(t, final.PartialSummaries, len(maps))
+	assert.Contains(t, buildPrompt(final), "=== Batch 1 ===\nnotes 1")
+	assert.Equal(t, fmt.Sprintf("notes %d", len(inner.requests)), resp.Summary)
+}
+
+func TestPack(t *testing.T) {
+	assert.Equal(t, [][2]int{{0, 2}, {2, 3}, {3, 5}}, pack([]int{3, 4, 9, 2, 2}, 8))
+	assert.Empty(t, pack(nil, 8))
+}
+
+func TestContextWindow(t *testing.T) {
+	assert.Equal(t, 128_000, ContextWindow(OpenAI, "gpt-4o-mini"))
+	assert.Equal(t, 200_000, ContextWindow(Claude, "claude-sonnet-4-5"))
+	assert.Equal(t, 1_048_576, ContextWindow(Gemini, "gemini-2.5-flash-lite"))
+	assert.Equal(t, 4_096, ContextWindow(Ollama, "llama3.2"))
+	assert.Equal(t, 128_000, ContextWindow(OpenAI, "some-future-model"))
+}
@@ -29,13 +29,19 @@ func NewOpenAIClient(config ClientConfig) (Client, error) {
 
 	// Set default model
 	if config.Model == "" {
-		config.Model = "gpt-4o-mini"
+		config.Model = getDefaultModel(OpenAI)
 	}
 
 	// Create client using official OpenAI package
-	client := openai.NewClient(
+	opts := []option.RequestOption{
 		option.WithAPIKey(config.APIKey),
-	)
+		// Retries are handled by RetryClient so backoff and Retry-After are applied once
+		option.WithMaxRetries(0),
+	}
+	if config.BaseURL != "" {
+		opts = append(opts, option.WithBaseURL(config.BaseURL))
+	}
+	client := openai.NewClient(opts...)
 
 	return &OpenAIClient{
 		client: &client,
@@ -43,34 +49,82 @@ func NewOpenAIClient(config ClientConfig) (Client, error) {
 	}, nil
 }
 
-func (c *OpenAIClient) Summarize(ctx context.Context, request *SummaryRequest) (*SummaryResponse, error) {
-	systemPrompt := getSystemPrompt(request.Platform)
-	userContext := buildPrompt(request)
-	fullPrompt := fmt.Sprintf("%s\n\n%s", systemPrompt, userContext)
+// providerName names the provider in responses and errors; LocalClient reuses these methods
+func (c *OpenAIClient) providerName() Provider {
+	if c.config.Provider != "" {
+		return c.config.Provider
+	}
+	return OpenAI
+}
+
+// chatParams builds the chat completion request shared by Summarize and SummarizeStream
+func (c *OpenAIClient) chatParams(request *SummaryRequest) openai.ChatCompletionNewParams {
+	systemPrompt, userPrompt := providerPrompt(c.providerName(), request)
 
 	// Prepare messages for OpenAI chat completion
 	messages := []openai.ChatCompletionMessageParamUnion{
 		openai.SystemMessage(systemPrompt),
-		openai.UserMessage(fullPrompt),
+		openai.UserMessage(userPrompt),
 	}
 
-	// Call OpenAI API
-	resp, err := c.client.Chat.Completions.New(ctx, openai.ChatCompletionNewParams{
+	return openai.ChatCompletionNewParams{
 		Model:               c.config.Model,
 		Messages:            messages,
-		Temperature:         param.Opt[float64]{Value: 0.7},
-		MaxCompletionTokens: param.Opt[int64]{Value: int64(getMaxTokensForPlatform(request.Platform))},
-	})
+		Temperature:         param.Opt[float64]{Value: c.config.temperature()},
+		MaxCompletionTokens: param.Opt[int64]{Value: int64(c.config.maxTokens(request.Platform))},
+	}
+}
+
+func (c *OpenAIClient) Summarize(ctx context.Context, request *SummaryRequest) (*SummaryResponse, error) {
+	// Call OpenAI API
+	resp, err := c.client.Chat.Completions.New(ctx, c.chatParams(request))
 	if err != nil {
-		return nil, fmt.Errorf("failed to generate summary: %w", err)
+		return nil, fmt.Errorf("failed to generate summary: %w", classifyError(c.providerName(), err))
 	}
 	if len(resp.Choices) == 0 {
 		return nil, fmt.Errorf("no choices returned from OpenAI API")
 	}
+	if reason := resp.Choices[0].FinishReason; reason == "content_filter" {
+		return nil, contentFilterError(c.providerName(), reason)
+	}
 	summary := strings.TrimSpace(resp.Choices[0].Message.Content)
 
 	return &SummaryResponse{
 		Summary:  summary,
 		Platform: request.Platform,
+		Provider: c.providerName(),
+	}, nil
+}
+
+// SummarizeStream streams the completion, calling onDelta with each text chunk
+func (c *OpenAIClient) SummarizeStream(ctx context.Context, request *SummaryRequest, onDelta func(string)) (*SummaryResponse, error) {
+	stream := c.client.Chat.Completions.NewStreaming(ctx, c.chatParams(request))
+	defer stream.Close()
+
+	var summary strings.Builder
+	for stream.Next() {
+		chunk := stream.Current()
+		if len(chunk.Choices) == 0 {
+			continue
+		}
+		if reason := chunk.Choices[0].FinishReason; reason == "content_filter" {
+			return nil, contentFilterError(c.providerName(), reason)
+		}
+		if delta := chunk.Choices[0].Delta.Content; delta != "" {
+			summary.WriteString(delta)
+			onDelta(delta)
+		}
+	}
+	if err := stream.Err(); err != nil {
+		return nil, fmt.Errorf("failed to generate summary: %w", classifyError(c.providerName(), err))
+	}
+	if summary.Len() == 0 {
+		return nil, fmt.Errorf("no content streamed from OpenAI API")
+	}
+
+	return &SummaryResponse{
+		Summary:  strings.TrimSpace(summary.String()),
+		Platform: request.Platform,
+		Provider: c.providerName(),
 	}, nil
 }
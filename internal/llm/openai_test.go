@@ -0,0 +1,52 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenAIClientSummarizeStream(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/chat/completions", r.URL.Path)
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, delta := range []string{"Shipped ", "streaming ", "output."} {
+			fmt.Fprintf(w, "data: {\"id\":\"1\",\"object\":\"chat.completion.chunk\",\"model\":\"gpt-4o\",\"choices\":[{\"index\":0,\"delta\":{\"content\":%q}}]}\n\n", delta)
+		}
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+	defer server.Close()
+
+	client, err := NewOpenAIClient(ClientConfig{Provider: OpenAI, APIKey: "sk-test", BaseURL: server.URL})
+	require.NoError(t, err)
+
+	var deltas []string
+	resp, err := SummarizeStream(context.Background(), client, &SummaryRequest{Platform: Note}, func(delta string) {
+		deltas = append(deltas, delta)
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Shipped ", "streaming ", "output."}, deltas)
+	assert.Equal(t, "Shipped streaming output.", resp.Summary)
+	assert.Equal(t, Note, resp.Platform)
+}
+
+func TestSummarizeStream_FallsBackToSummarize(t *testing.T) {
+	// Claude has no streaming implementation, so the whole summary arrives as one delta
+	server := newClaudeStub(t, http.StatusOK, `{"content":[{"type":"text","text":"whole summary"}],"stop_reason":"end_turn"}`, nil)
+	client, err := NewClaudeClient(ClientConfig{Provider: Claude, APIKey: "test-key", BaseURL: server.URL})
+	require.NoError(t, err)
+
+	var streamed strings.Builder
+	resp, err := SummarizeStream(context.Background(), client, &SummaryRequest{Platform: Note}, func(delta string) {
+		streamed.WriteString(delta)
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "whole summary", streamed.String())
+	assert.Equal(t, "whole summary", resp.Summary)
+}
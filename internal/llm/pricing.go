@@ -0,0 +1,45 @@
+package llm
+
+import "strings"
+
+// modelPrices lists list prices in USD per million input and output tokens,
+// matched by model name prefix. Longer prefixes come before the ones they extend.
+var modelPrices = []struct {
+	prefix string
+	input  float64
+	output float64
+}{
+	{"gpt-5-nano", 0.05, 0.40},
+	{"gpt-5-mini", 0.25, 2.00},
+	{"gpt-5", 1.25, 10.00},
+	{"gpt-4.1-nano", 0.10, 0.40},
+	{"gpt-4.1-mini", 0.40, 1.60},
+	{"gpt-4.1", 2.00, 8.00},
+	{"gpt-4o-mini", 0.15, 0.60},
+	{"gpt-4o", 2.50, 10.00},
+	{"o4-mini", 1.10, 4.40},
+	{"o3", 2.00, 8.00},
+	{"claude-opus-4", 15.00, 75.00},
+	{"claude-sonnet-4", 3.00, 15.00},
+	{"claude-haiku-4", 1.00, 5.00},
+	{"claude-3-5-haiku", 0.80, 4.00},
+	{"gemini-2.5-pro", 1.25, 10.00},
+	{"gemini-2.5-flash-lite", 0.10, 0.40},
+	{"gemini-2.5-flash", 0.30, 2.50},
+	{"gemini-2.0-flash", 0.10, 0.40},
+}
+
+// EstimateCost returns the cost in USD of a request with the given token
+// counts, and false when the model's price isn't known. Local providers are free.
+func EstimateCost(provider Provider, model string, inputTokens, outputTokens int) (float64, bool) {
+	if isLocalProvider(provider) {
+		return 0, true
+	}
+	model = strings.ToLower(model)
+	for _, price := range modelPrices {
+		if strings.HasPrefix(model, price.prefix) {
+			return (float64(inputTokens)*price.input + float64(outputTokens)*price.output) / 1_000_000, true
+		}
+	}
+	return 0, false
+}
@@ -3,6 +3,9 @@ package llm
 import (
 	"fmt"
 	"strings"
+
+	"github.com/frfahim/gitstory/internal/conventional"
+	"github.com/frfahim/gitstory/internal/types"
 )
 
 // getSystemPrompt returns the system prompt for different platforms
@@ -42,6 +45,30 @@ func getSystemPrompt(platform Platform) string {
 - Creating concise but complete summarize
 - Noting important context and follow-up actions
 - Structuring information for personal productivity and growth tracking`,
+
+		Batch: `You are a senior engineer condensing one slice of a long commit history. You excel at:
+- Capturing every significant feature, fix, refactor and breaking change
+- Keeping file, module and function names that a later summary will need
+- Dropping noise such as formatting-only or trivial commits
+- Writing dense, factual notes rather than polished prose`,
+
+		Changelog: `You are a release manager writing a project's changelog for its users. You excel at:
+- Explaining what changed from the user's point of view, not the implementation
+- Spotting which commits users will notice and which are internal
+- Merging related commits into one clear entry
+- Calling out breaking changes together with what users need to do`,
+
+		VersionBump: `You are a release engineer who applies Semantic Versioning strictly. You excel at:
+- Reading diffs for changes to public APIs, CLI flags, config formats and behavior
+- Spotting breaking changes that commit messages don't mention
+- Telling new functionality apart from fixes and internal changes
+- Explaining a versioning decision in a few precise sentences`,
+
+		CommitMessage: `You are a senior engineer writing the commit message for a change you are about to commit. You excel at:
+- Summarizing the intent of a diff in one precise, imperative line
+- Choosing the Conventional Commits type and scope that fit the change
+- Explaining why a change was made when the diff alone doesn't show it
+- Keeping messages short and free of filler`,
 	}
 
 	if prompt, exists := prompts[platform]; exists {
@@ -149,6 +176,39 @@ Create organized personal notes:
 - [ ] Ideas for future improvements
 
 Use bullet points and checkboxes. Keep it concise but complete for future reference.`,
+
+		Batch: `
+Create intermediate notes that will be merged with notes on other commits:
+- One bullet per significant change, grouped by feature or area
+- Keep concrete names (files, modules, functions, flags) and numbers
+- Call out breaking changes, migrations and security fixes explicitly
+- No introduction, conclusion or marketing language
+- Aim for at most 300 words`,
+
+		Changelog: `
+Write the entries of one release for a Keep a Changelog file:
+- Use only these headings, in this order, and leave out empty ones: ### Added, ### Changed, ### Deprecated, ### Removed, ### Fixed, ### Security
+- One "- " bullet per change users would notice, in plain language; merge related commits
+- Leave out internal changes such as tests, CI, build tooling and refactors with no visible effect
+- List breaking changes first under ### Changed, starting with "**Breaking:**", and say what users need to do
+- Output only the markdown sections: no release heading, introduction or closing remarks`,
+
+		VersionBump: `
+Decide the Semantic Versioning bump these commits call for since the last release:
+- major: anything that can break existing users (removed or renamed APIs, flags or config keys, changed defaults or output formats)
+- minor: new backwards compatible functionality
+- patch: backwards compatible fixes only
+- none: nothing users can notice (docs, tests, CI, refactors without visible effect)
+Answer with the bump alone on the first line: major, minor, patch or none.
+Then give at most 3 short bullets with the changes that decided it.`,
+
+		CommitMessage: `
+Write a Conventional Commits message for these changes:
+- Header: "type(scope): subject", with type one of feat, fix, docs, style, refactor, perf, test, build, ci, chore or revert; leave out the scope if no single area fits
+- Subject in the imperative mood, lowercase, no trailing period, header at most 72 characters
+- Mark breaking changes with "!" after the type or scope and a "BREAKING CHANGE: " footer saying what users need to do
+- Add a body after a blank line only when the change needs explaining; focus on what and why, wrapped at 72 characters
+- Output only the commit message: no code fences, quotes or commentary`,
 	}
 
 	if instruction, exists := instructions[platform]; exists {
@@ -160,11 +220,15 @@ Use bullet points and checkboxes. Keep it concise but complete for future refere
 // getMaxTokensForPlatform returns appropriate token limits for each platform
 func getMaxTokensForPlatform(platform Platform) int {
 	limits := map[Platform]int{
-		Twitter:   150,  // Short
-		LinkedIn:  400,  // Professional detail
-		Blog:      1000, // Rich content
-		Technical: 800,  // Detailed but focused
-		Note:      500,  // Personal note
+		Twitter:       150,  // Short
+		LinkedIn:      400,  // Professional detail
+		Blog:          1000, // Rich content
+		Technical:     800,  // Detailed but focused
+		Note:          500,  // Personal note
+		Batch:         600,  // Intermediate map-reduce notes
+		Changelog:     600,  // One release's changelog entries
+		VersionBump:   300,  // A bump and a few reasons
+		CommitMessage: 300,  // A header and a short body
 	}
 
 	if limit, exists := limits[platform]; exists {
@@ -173,6 +237,25 @@ func getMaxTokensForPlatform(platform Platform) int {
 	return 400 // default
 }
 
+// providerPrompt returns the system and user messages exactly as the provider's
+// client sends them. The system message is empty when the provider takes a single prompt.
+func providerPrompt(provider Provider, request *SummaryRequest) (system, user string) {
+	systemPrompt := getSystemPrompt(request.Platform)
+	userPrompt := buildPrompt(request)
+
+	switch provider {
+	case Gemini:
+		// For Gemini, we need to combine system and user prompts since it doesn't have separate system messages
+		// We'll structure it as: "You are X. Here's the task: Y"
+		return "", fmt.Sprintf("%s\n\n--- TASK ---\n%s", systemPrompt, userPrompt)
+	case Claude:
+		return systemPrompt, userPrompt
+	default:
+		// OpenAI-style clients repeat the system prompt at the top of the user message
+		return systemPrompt, fmt.Sprintf("%s\n\n%s", systemPrompt, userPrompt)
+	}
+}
+
 // buildPrompt creates the prompt for any AI provider based on commits and context
 func buildPrompt(request *SummaryRequest) string {
 	var prompt strings.Builder
@@ -182,62 +265,40 @@ func buildPrompt(request *SummaryRequest) string {
 		prompt.WriteString(fmt.Sprintf("Project Context: %s\n\n", request.UserContext))
 	}
 
-	// Add commit summary stats
-	prompt.WriteString(fmt.Sprintf("Analyzing %d git commit(s) with code changes:\n\n", len(request.Commits)))
-
-	// Add each commit with enhanced formatting
-	for i, commit := range request.Commits {
-		prompt.WriteString(fmt.Sprintf("=== Commit %d ===\n", i+1))
-		// prompt.WriteString(fmt.Sprintf("• Hash: %s\n", commit.Hash))
-		prompt.WriteString(fmt.Sprintf("• Author: %s\n", commit.Author))
-		prompt.WriteString(fmt.Sprintf("• Date: %s\n", commit.Date))
-		prompt.WriteString(fmt.Sprintf("• Message: %s\n", commit.Message))
-
-		// Add file statistics (always available from ListCommitSummarize)
-		if commit.Stats.TotalFiles > 0 {
-			prompt.WriteString(fmt.Sprintf("• Files changed: %d\n", commit.Stats.TotalFiles))
-			prompt.WriteString(fmt.Sprintf("• Lines: +%d -%d\n", commit.Stats.Additions, commit.Stats.Deletions))
-
-			if commit.Stats.PrimaryLang != "" {
-				prompt.WriteString(fmt.Sprintf("• Primary language: %s\n", commit.Stats.PrimaryLang))
-			}
-
-			if len(commit.Stats.Languages) > 1 {
-				prompt.WriteString(fmt.Sprintf("• Languages: %s\n", strings.Join(commit.Stats.Languages, ", ")))
-			}
+	if len(request.PartialSummaries) > 0 {
+		// Reduce step: combine the notes written for each batch of commits
+		prompt.WriteString(fmt.Sprintf("Combining notes on %d batch(es) of git commits, newest batch first:\n\n", len(request.PartialSummaries)))
+		for i, partial := range request.PartialSummaries {
+			prompt.WriteString(fmt.Sprintf("=== Batch %d ===\n%s\n\n", i+1, strings.TrimSpace(partial)))
 		}
-
-		// Add file changes (always available from ListCommitSummarize)
-		if len(commit.Files) > 0 {
-			prompt.WriteString("• File changes:\n")
-			for _, file := range commit.Files {
-				prompt.WriteString(fmt.Sprintf("  - %s (%s)", file.Path, file.Status))
-				if file.Additions > 0 || file.Deletions > 0 {
-					prompt.WriteString(fmt.Sprintf(" [+%d -%d]", file.Additions, file.Deletions))
-				}
-				prompt.WriteString("\n")
-
-				// Include code changes if available
-				if file.Content != "" {
-					prompt.WriteString("    Code changes:\n")
-					// Limit the content for prompt efficiency
-					contentLines := strings.Split(file.Content, "\n")
-					maxLines := 15 // Reasonable limit for prompts
-					if len(contentLines) > maxLines {
-						contentLines = contentLines[:maxLines]
-						contentLines = append(contentLines, "... (truncated)")
-					}
-
-					for _, line := range contentLines {
-						if line != "" {
-							prompt.WriteString(fmt.Sprintf("    %s\n", line))
-						}
-					}
-					prompt.WriteString("\n")
+	} else if request.Platform == CommitMessage {
+		// The changes aren't committed yet, so there is no author, date or message
+		prompt.WriteString("Uncommitted changes to describe:\n\n")
+		for _, commit := range request.Commits {
+			writeChanges(&prompt, commit)
+		}
+		prompt.WriteString("\n")
+	} else {
+		// Add commit summary stats
+		prompt.WriteString(fmt.Sprintf("Analyzing %d git commit(s) with code changes:\n\n", len(request.Commits)))
+
+		if conventional.Any(request.Commits) {
+			// Conventional Commits are presented by type, so breaking changes,
+			// features and fixes stand out from the rest
+			index := 1
+			for _, section := range conventional.Group(request.Commits, "feat", "fix") {
+				prompt.WriteString(fmt.Sprintf("--- %s ---\n\n", strings.ToUpper(section.Title)))
+				for _, commit := range section.Commits {
+					writeCommit(&prompt, index, commit)
+					index++
 				}
 			}
+		} else {
+			// Add each commit with enhanced formatting
+			for i, commit := range request.Commits {
+				writeCommit(&prompt, i+1, commit)
+			}
 		}
-		prompt.WriteString("\n")
 	}
 
 	// Add platform-specific instructions
@@ -254,3 +315,123 @@ func buildPrompt(request *SummaryRequest) string {
 
 	return prompt.String()
 }
+
+// writeCommit appends one commit's section of the prompt
+func writeCommit(prompt *strings.Builder, index int, commit types.CommitData) {
+	prompt.WriteString(fmt.Sprintf("=== Commit %d ===\n", index))
+	// prompt.WriteString(fmt.Sprintf("• Hash: %s\n", commit.Hash))
+	prompt.WriteString(fmt.Sprintf("• Author: %s\n", commit.Author))
+	prompt.WriteString(fmt.Sprintf("• Date: %s\n", commit.Date))
+	prompt.WriteString(fmt.Sprintf("• Message: %s\n", commit.Message))
+	if cc := commit.Conventional; cc != nil {
+		if cc.Scope != "" {
+			prompt.WriteString(fmt.Sprintf("• Type: %s (scope: %s)\n", cc.Type, cc.Scope))
+		} else {
+			prompt.WriteString(fmt.Sprintf("• Type: %s\n", cc.Type))
+		}
+		if cc.Breaking {
+			note := cc.BreakingNote
+			if note == "" {
+				note = cc.Subject
+			}
+			prompt.WriteString(fmt.Sprintf("• Breaking change: %s\n", note))
+		}
+	}
+	writeChanges(prompt, commit)
+	prompt.WriteString("\n")
+}
+
+// writeChanges appends the file statistics and changes of a commit
+func writeChanges(prompt *strings.Builder, commit types.CommitData) {
+	// Add file statistics (always available from ListCommitSummarize)
+	if commit.Stats.TotalFiles > 0 {
+		prompt.WriteString(fmt.Sprintf("• Files changed: %d\n", commit.Stats.TotalFiles))
+		prompt.WriteString(fmt.Sprintf("• Lines: +%d -%d\n", commit.Stats.Additions, commit.Stats.Deletions))
+
+		if commit.Stats.PrimaryLang != "" {
+			prompt.WriteString(fmt.Sprintf("• Primary language: %s\n", commit.Stats.PrimaryLang))
+		}
+
+		if len(commit.Stats.Languages) > 1 {
+			prompt.WriteString(fmt.Sprintf("• Languages: %s\n", strings.Join(commit.Stats.Languages, ", ")))
+		}
+	}
+
+	// Add file changes (always available from ListCommitSummarize)
+	if len(commit.Files) > 0 {
+		prompt.WriteString("• File changes:\n")
+		for _, file := range commit.Files {
+			if file.OldPath != "" {
+				prompt.WriteString(fmt.Sprintf("  - %s (%s from %s)", file.Path, file.Status, file.OldPath))
+			} else {
+				prompt.WriteString(fmt.Sprintf("  - %s (%s)", file.Path, file.Status))
+			}
+			if file.Additions > 0 || file.Deletions > 0 {
+				prompt.WriteString(fmt.Sprintf(" [+%d -%d]", file.Additions, file.Deletions))
+			}
+			if file.Excluded != "" {
+				prompt.WriteString(fmt.Sprintf(" (%s, content omitted)", file.Excluded))
+			}
+			if file.Binary || file.Truncated {
+				prompt.WriteString(" " + describeSize(file))
+			}
+			prompt.WriteString("\n")
+
+			// Include code changes if available
+			if file.Content != "" {
+				prompt.WriteString("    Code changes:\n")
+				// Limit the content for prompt efficiency
+				contentLines := strings.Split(file.Content, "\n")
+				maxLines := 15 // Reasonable limit for prompts
+				if len(contentLines) > maxLines {
+					contentLines = contentLines[:maxLines]
+					contentLines = append(contentLines, "... (truncated)")
+				}
+
+				for _, line := range contentLines {
+					if line != "" {
+						prompt.WriteString(fmt.Sprintf("    %s\n", line))
+					}
+				}
+				prompt.WriteString("\n")
+			}
+		}
+	}
+}
+
+// describeSize summarizes a binary or undiffed file by its sizes,
+// e.g. "(binary, 45 KB → 52 KB)" or "(2.1 MB, too large to diff)"
+func describeSize(file types.FileChange) string {
+	var size string
+	switch {
+	case file.OldSize > 0 && file.NewSize > 0:
+		size = formatBytes(file.OldSize) + " → " + formatBytes(file.NewSize)
+	case file.NewSize > 0:
+		size = formatBytes(file.NewSize)
+	default:
+		size = formatBytes(file.OldSize)
+	}
+	if file.Binary {
+		return "(binary, " + size + ")"
+	}
+	return "(" + size + ", too large to diff)"
+}
+
+// formatBytes renders a byte count with a binary unit, e.g. "45 KB" or "2.1 MB"
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	value, suffix := float64(n)/unit, "KB"
+	for _, next := range []string{"MB", "GB"} {
+		if value < unit {
+			break
+		}
+		value, suffix = value/unit, next
+	}
+	if value < 10 && suffix != "KB" {
+		return fmt.Sprintf("%.1f %s", value, suffix)
+	}
+	return fmt.Sprintf("%.0f %s", value, suffix)
+}
@@ -0,0 +1,64 @@
+package llm
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/frfahim/gitstory/internal/conventional"
+	"github.com/frfahim/gitstory/internal/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatBytes(t *testing.T) {
+	assert.Equal(t, "512 B", formatBytes(512))
+	assert.Equal(t, "45 KB", formatBytes(45*1024))
+	assert.Equal(t, "2.1 MB", formatBytes(2200*1024))
+	assert.Equal(t, "120 MB", formatBytes(120<<20))
+}
+
+func TestBuildPrompt_DescribesFiles(t *testing.T) {
+	prompt := buildPrompt(&SummaryRequest{Commits: []types.CommitData{{
+		Message: "Refresh assets",
+		Files: []types.FileChange{
+			{Path: "logo.png", Status: "Modify", Binary: true, OldSize: 45 * 1024, NewSize: 52 * 1024},
+			{Path: "dump.sql", Status: "Insert", Truncated: true, NewSize: 3 << 20},
+			{Path: "pkg/store/db.go", OldPath: "internal/db.go", Status: types.StatusRenamed, Additions: 1, Deletions: 1},
+		},
+	}}})
+	assert.Contains(t, prompt, "logo.png (Modify) (binary, 45 KB → 52 KB)")
+	assert.Contains(t, prompt, "dump.sql (Insert) (3.0 MB, too large to diff)")
+	assert.Contains(t, prompt, "pkg/store/db.go (Rename from internal/db.go) [+1 -1]")
+}
+
+func TestBuildPrompt_GroupsConventionalCommits(t *testing.T) {
+	commit := func(message string) types.CommitData {
+		return types.CommitData{Message: message, Conventional: conventional.Parse(message)}
+	}
+	prompt := buildPrompt(&SummaryRequest{Commits: []types.CommitData{
+		commit("fix(auth): refresh expired tokens"),
+		commit("feat: add SSO"),
+		commit("refactor!: rename config keys\n\nBREAKING CHANGE: env is now environment"),
+		commit("Update README"),
+	}})
+
+	breaking := strings.Index(prompt, "--- BREAKING CHANGES ---")
+	features := strings.Index(prompt, "--- FEATURES ---")
+	fixes := strings.Index(prompt, "--- BUG FIXES ---")
+	other := strings.Index(prompt, "--- OTHER CHANGES ---")
+	assert.True(t, breaking >= 0 && breaking < features && features < fixes && fixes < other, prompt)
+	assert.Contains(t, prompt, "• Breaking change: env is now environment")
+	assert.Contains(t, prompt, "• Type: fix (scope: auth)")
+	assert.Contains(t, prompt, "=== Commit 4 ===\n• Author: \n• Date: \n• Message: Update README")
+}
+
+func TestBuildPrompt_CommitMessage(t *testing.T) {
+	prompt := buildPrompt(&SummaryRequest{Platform: CommitMessage, Commits: []types.CommitData{{
+		Stats: types.CommitStats{TotalFiles: 1, Additions: 2},
+		Files: []types.FileChange{{Path: "cmd/root.go", Status: "Modify", Additions: 2, Content: "+verbose := true"}},
+	}}})
+	assert.Contains(t, prompt, "Uncommitted changes to describe:\n\n• Files changed: 1\n")
+	assert.Contains(t, prompt, "  - cmd/root.go (Modify) [+2 -0]\n    Code changes:\n    +verbose := true\n")
+	assert.NotContains(t, prompt, "=== Commit")
+	assert.NotContains(t, prompt, "• Author:")
+	assert.Contains(t, prompt, "Write a Conventional Commits message")
+}
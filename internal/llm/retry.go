@@ -0,0 +1,138 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"time"
+)
+
+// RetryPolicy controls how RetryClient retries transient failures
+type RetryPolicy struct {
+	// MaxRetries is the number of retries after the first attempt
+	MaxRetries int
+	// BaseDelay is the backoff before the first retry; it doubles on each retry
+	BaseDelay time.Duration
+	// MaxDelay caps the backoff. A Retry-After longer than this is not waited out.
+	MaxDelay time.Duration
+	// Timeout bounds each attempt; zero means no per-attempt timeout
+	Timeout time.Duration
+	// OnRetry, if set, is called before sleeping between attempts
+	OnRetry func(attempt int, delay time.Duration, err *Error)
+}
+
+// DefaultRetryPolicy retries rate limits, network errors and 5xx responses three times
+var DefaultRetryPolicy = RetryPolicy{
+	MaxRetries: 3,
+	BaseDelay:  time.Second,
+	MaxDelay:   time.Minute,
+}
+
+// RetryClient wraps a Client with exponential backoff and jitter, honoring
+// the provider's Retry-After. Only errors whose Kind is retryable are retried.
+type RetryClient struct {
+	client Client
+	policy RetryPolicy
+	sleep  func(ctx context.Context, d time.Duration) error
+}
+
+// NewRetryClient wraps client with the given retry policy
+func NewRetryClient(client Client, policy RetryPolicy) *RetryClient {
+	return &RetryClient{client: client, policy: policy, sleep: sleepContext}
+}
+
+// GetProvider returns the wrapped client's provider
+func (c *RetryClient) GetProvider() Provider {
+	return c.client.GetProvider()
+}
+
+func (c *RetryClient) Summarize(ctx context.Context, request *SummaryRequest) (*SummaryResponse, error) {
+	return c.do(ctx, func(ctx context.Context) (*SummaryResponse, bool, error) {
+		response, err := c.client.Summarize(ctx, request)
+		return response, true, err
+	})
+}
+
+// SummarizeStream streams through the wrapped client. An attempt that fails
+// after text was already delivered is not retried, since the caller has
+// printed part of the answer.
+func (c *RetryClient) SummarizeStream(ctx context.Context, request *SummaryRequest, onDelta func(string)) (*SummaryResponse, error) {
+	return c.do(ctx, func(ctx context.Context) (*SummaryResponse, bool, error) {
+		streamed := false
+		response, err := SummarizeStream(ctx, c.client, request, func(delta string) {
+			streamed = true
+			onDelta(delta)
+		})
+		return response, !streamed, err
+	})
+}
+
+// do runs attempt until it succeeds, fails with a non-retryable error or the
+// retries are used up. attempt reports whether a failure may be retried.
+func (c *RetryClient) do(ctx context.Context, attempt func(ctx context.Context) (*SummaryResponse, bool, error)) (*SummaryResponse, error) {
+	for retry := 0; ; retry++ {
+		response, retryable, err := c.attempt(ctx, attempt)
+		if err == nil {
+			return response, nil
+		}
+
+		var llmErr *Error
+		if !retryable || retry >= c.policy.MaxRetries || ctx.Err() != nil ||
+			!errors.As(err, &llmErr) || !llmErr.Retryable() {
+			return nil, err
+		}
+
+		delay := c.backoff(retry, llmErr.RetryAfter)
+		if delay < 0 {
+			return nil, err
+		}
+		if c.policy.OnRetry != nil {
+			c.policy.OnRetry(retry+1, delay, llmErr)
+		}
+		if err := c.sleep(ctx, delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (c *RetryClient) attempt(ctx context.Context, attempt func(ctx context.Context) (*SummaryResponse, bool, error)) (*SummaryResponse, bool, error) {
+	if c.policy.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.policy.Timeout)
+		defer cancel()
+	}
+	return attempt(ctx)
+}
+
+// backoff returns the delay before the given retry: the provider's Retry-After
+// when present, otherwise jittered exponential backoff. It returns -1 when
+// the provider asks for a longer wait than MaxDelay.
+func (c *RetryClient) backoff(retry int, retryAfter time.Duration) time.Duration {
+	if retryAfter > 0 {
+		if c.policy.MaxDelay > 0 && retryAfter > c.policy.MaxDelay {
+			return -1
+		}
+		return retryAfter
+	}
+
+	delay := c.policy.BaseDelay << retry
+	if c.policy.MaxDelay > 0 && (delay > c.policy.MaxDelay || delay <= 0) {
+		delay = c.policy.MaxDelay
+	}
+	if delay <= 0 {
+		return 0
+	}
+	// Equal jitter: anywhere between half and the whole delay
+	return delay/2 + rand.N(delay/2+1)
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
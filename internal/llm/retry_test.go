@@ -0,0 +1,95 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// scriptedClient returns the queued errors in order, then succeeds
+type scriptedClient struct {
+	errs  []error
+	calls int
+}
+
+func (c *scriptedClient) GetProvider() Provider { return OpenAI }
+
+func (c *scriptedClient) Summarize(ctx context.Context, request *SummaryRequest) (*SummaryResponse, error) {
+	c.calls++
+	if len(c.errs) > 0 {
+		err := c.errs[0]
+		c.errs = c.errs[1:]
+		return nil, err
+	}
+	return &SummaryResponse{Summary: "ok", Platform: request.Platform}, nil
+}
+
+func newTestRetryClient(client Client, policy RetryPolicy) (*RetryClient, *[]time.Duration) {
+	var slept []time.Duration
+	retrying := NewRetryClient(client, policy)
+	retrying.sleep = func(ctx context.Context, d time.Duration) error {
+		slept = append(slept, d)
+		return nil
+	}
+	return retrying, &slept
+}
+
+func TestRetryClient_RetriesTransientErrors(t *testing.T) {
+	inner := &scriptedClient{errs: []error{
+		&Error{Kind: ErrRateLimit, Provider: OpenAI, RetryAfter: 3 * time.Second},
+		&Error{Kind: ErrServer, Provider: OpenAI},
+	}}
+	client, slept := newTestRetryClient(inner, RetryPolicy{MaxRetries: 3, BaseDelay: time.Second, MaxDelay: time.Minute})
+
+	resp, err := client.Summarize(context.Background(), &SummaryRequest{Platform: Note})
+	require.NoError(t, err)
+	assert.Equal(t, "ok", resp.Summary)
+	assert.Equal(t, 3, inner.calls)
+
+	require.Len(t, *slept, 2)
+	assert.Equal(t, 3*time.Second, (*slept)[0], "Retry-After is honored")
+	assert.GreaterOrEqual(t, (*slept)[1], time.Second, "second retry backs off from 2s with jitter")
+	assert.LessOrEqual(t, (*slept)[1], 2*time.Second)
+}
+
+func TestRetryClient_DoesNotRetryPermanentErrors(t *testing.T) {
+	for _, err := range []error{
+		&Error{Kind: ErrAuth, Provider: OpenAI},
+		&Error{Kind: ErrQuota, Provider: OpenAI},
+		errors.New("unclassified"),
+	} {
+		inner := &scriptedClient{errs: []error{err}}
+		client, slept := newTestRetryClient(inner, DefaultRetryPolicy)
+
+		_, got := client.Summarize(context.Background(), &SummaryRequest{})
+		assert.Equal(t, err, got)
+		assert.Equal(t, 1, inner.calls)
+		assert.Empty(t, *slept)
+	}
+}
+
+func TestRetryClient_GivesUp(t *testing.T) {
+	rateLimited := &Error{Kind: ErrRateLimit, Provider: OpenAI}
+	inner := &scriptedClient{errs: []error{rateLimited, rateLimited, rateLimited}}
+	client, _ := newTestRetryClient(inner, RetryPolicy{MaxRetries: 2, BaseDelay: time.Millisecond})
+
+	_, err := client.Summarize(context.Background(), &SummaryRequest{})
+	var llmErr *Error
+	require.ErrorAs(t, err, &llmErr)
+	assert.Equal(t, ErrRateLimit, llmErr.Kind)
+	assert.Equal(t, 3, inner.calls)
+}
+
+func TestRetryClient_RetryAfterBeyondMaxDelay(t *testing.T) {
+	inner := &scriptedClient{errs: []error{&Error{Kind: ErrRateLimit, RetryAfter: time.Hour}}}
+	client, slept := newTestRetryClient(inner, DefaultRetryPolicy)
+
+	_, err := client.Summarize(context.Background(), &SummaryRequest{})
+	require.Error(t, err)
+	assert.Equal(t, 1, inner.calls)
+	assert.Empty(t, *slept)
+}
@@ -0,0 +1,73 @@
+package llm
+
+import (
+	"math"
+	"strings"
+)
+
+// charsPerToken is a rough characters-per-token ratio for each provider's
+// tokenizer on English text mixed with code
+var charsPerToken = map[Provider]float64{
+	OpenAI:           4.0,
+	Gemini:           4.0,
+	Claude:           3.5,
+	Ollama:           3.5,
+	OpenAICompatible: 3.5,
+}
+
+// EstimateTokens approximates how many tokens text uses with the provider's tokenizer.
+// It errs on the high side so budgets computed from it stay within the real limit.
+func EstimateTokens(provider Provider, text string) int {
+	ratio, ok := charsPerToken[provider]
+	if !ok {
+		ratio = 3.5
+	}
+	return int(math.Ceil(float64(len(text)) / ratio))
+}
+
+// contextWindows maps model name prefixes to their context size in tokens.
+// Longer prefixes are listed before the shorter ones they extend.
+var contextWindows = []struct {
+	prefix string
+	tokens int
+}{
+	{"gpt-5", 400_000},
+	{"gpt-4.1", 1_047_576},
+	{"gpt-4o", 128_000},
+	{"gpt-4-turbo", 128_000},
+	{"gpt-4", 8_192},
+	{"gpt-3.5-turbo", 16_385},
+	{"o1", 200_000},
+	{"o3", 200_000},
+	{"o4", 200_000},
+	{"claude-", 200_000},
+	{"gemini-1.5-pro", 2_097_152},
+	{"gemini-", 1_048_576},
+}
+
+// defaultContextWindows is used when the model isn't recognized. Ollama
+// silently truncates prompts beyond its default num_ctx, so it is kept small.
+var defaultContextWindows = map[Provider]int{
+	OpenAI:           128_000,
+	Claude:           200_000,
+	Gemini:           1_048_576,
+	Ollama:           4_096,
+	OpenAICompatible: 8_192,
+}
+
+// ContextWindow returns the context size in tokens for a provider's model
+func ContextWindow(provider Provider, model string) int {
+	// Local servers decide the context size themselves, whatever the model supports
+	if !isLocalProvider(provider) {
+		model = strings.ToLower(model)
+		for _, window := range contextWindows {
+			if strings.HasPrefix(model, window.prefix) {
+				return window.tokens
+			}
+		}
+	}
+	if tokens, ok := defaultContextWindows[provider]; ok {
+		return tokens
+	}
+	return 8_192
+}
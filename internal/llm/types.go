@@ -15,6 +15,10 @@ const (
 	OpenAI Provider = "openai"
 	Claude Provider = "claude"
 	Gemini Provider = "gemini"
+
+	// Self-hosted providers speaking the OpenAI chat completions protocol
+	Ollama           Provider = "ollama"
+	OpenAICompatible Provider = "openai-compatible"
 )
 
 // Platform represents target platforms for summarize
@@ -26,6 +30,22 @@ const (
 	Blog      Platform = "blog"      // Blog posts
 	Note      Platform = "note"      // Personal notes
 	Technical Platform = "technical" // Technical documentation
+
+	// Batch is used internally for the intermediate summaries of a map-reduce run;
+	// it is not a user-selectable platform
+	Batch Platform = "batch"
+
+	// Changelog rewrites a release's commits as Keep a Changelog entries for
+	// `gitstory changelog --ai`; it is not selectable with --platform
+	Changelog Platform = "changelog"
+
+	// VersionBump judges the semantic version bump of a set of commits for
+	// `gitstory next-version --ai`; it is not selectable with --platform
+	VersionBump Platform = "version-bump"
+
+	// CommitMessage writes a Conventional Commits message for uncommitted
+	// changes for `gitstory commit-msg`; it is not selectable with --platform
+	CommitMessage Platform = "commit-message"
 )
 
 // NormalizePlatform converts platform aliases to canonical names
@@ -58,11 +78,62 @@ type Client interface {
 	GetProvider() Provider
 }
 
+// StreamingClient is implemented by clients that can deliver the summary as
+// it is generated. Use SummarizeStream to fall back to Summarize otherwise.
+type StreamingClient interface {
+	Client
+
+	// SummarizeStream calls onDelta with each chunk of text as it arrives and
+	// returns the assembled response once generation finishes
+	SummarizeStream(ctx context.Context, request *SummaryRequest, onDelta func(delta string)) (*SummaryResponse, error)
+}
+
+// SummarizeStream streams the summary through onDelta when the client supports
+// it; otherwise it calls Summarize and delivers the whole summary as one delta
+func SummarizeStream(ctx context.Context, client Client, request *SummaryRequest, onDelta func(delta string)) (*SummaryResponse, error) {
+	if streamer, ok := client.(StreamingClient); ok {
+		return streamer.SummarizeStream(ctx, request, onDelta)
+	}
+	response, err := client.Summarize(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	onDelta(response.Summary)
+	return response, nil
+}
+
 // ClientConfig contains configuration for AI clients
 type ClientConfig struct {
 	Provider Provider `json:"provider"`
 	APIKey   string   `json:"api_key"`
 	Model    string   `json:"model,omitempty"`
+	BaseURL  string   `json:"base_url,omitempty"` // Override the provider API endpoint
+
+	// Generation overrides; zero values fall back to defaults
+	Temperature *float64 `json:"temperature,omitempty"` // nil means defaultTemperature
+	MaxTokens   int      `json:"max_tokens,omitempty"`  // 0 means the platform's limit
+
+	// ContextWindow overrides the model's context size in tokens; 0 uses the known size
+	ContextWindow int `json:"context_window,omitempty"`
+}
+
+// defaultTemperature is used when ClientConfig.Temperature is unset
+const defaultTemperature = 0.7
+
+// temperature returns the sampling temperature to send to the provider
+func (c ClientConfig) temperature() float64 {
+	if c.Temperature != nil {
+		return *c.Temperature
+	}
+	return defaultTemperature
+}
+
+// maxTokens returns the output token limit for a request on the given platform
+func (c ClientConfig) maxTokens(platform Platform) int {
+	if c.MaxTokens > 0 {
+		return c.MaxTokens
+	}
+	return getMaxTokensForPlatform(platform)
 }
 
 // SummaryRequest contains all information needed for AI summarization
@@ -76,12 +147,18 @@ type SummaryRequest struct {
 
 	// Additional context provided by the user
 	UserContext string `json:"user_context,omitempty"`
+
+	// PartialSummaries are summaries of earlier commit batches; when set, the
+	// prompt combines them instead of listing Commits
+	PartialSummaries []string `json:"partial_summaries,omitempty"`
 }
 
 // SummaryResponse contains the AI-generated summary
 type SummaryResponse struct {
 	Summary  string   `json:"summary"`
 	Platform Platform `json:"platform"`
+	// Provider is the provider that produced the summary
+	Provider Provider `json:"provider,omitempty"`
 }
 
 // CharCount returns the character count of the summary
@@ -0,0 +1,184 @@
+// Package redact removes secrets and credentials from text before it is sent
+// to an LLM provider.
+package redact
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/frfahim/gitstory/internal/conventional"
+	"github.com/frfahim/gitstory/internal/types"
+)
+
+// Rule is a named secret pattern. When the pattern has a capture group named
+// "secret", only that group is replaced, so "password = hunter2" keeps its key.
+type Rule struct {
+	Name    string
+	Pattern *regexp.Regexp
+}
+
+// DefaultRules are the built-in secret patterns, most specific first
+var DefaultRules = []Rule{
+	{"private-key", regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY( BLOCK)?-----(?:[\s\S]*?-----END [A-Z ]*PRIVATE KEY( BLOCK)?-----|[\s\S]*)`)},
+	{"aws-access-key", regexp.MustCompile(`\b(?:AKIA|ASIA|ABIA|ACCA)[0-9A-Z]{16}\b`)},
+	{"aws-secret-key", regexp.MustCompile(`(?i)aws.{0,20}?(?:secret|key).{0,20}?[=:]\s*["']?(?P<secret>[A-Za-z0-9/+=]{40})\b`)},
+	{"github-token", regexp.MustCompile(`\b(?:gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{22,})\b`)},
+	{"api-key", regexp.MustCompile(`\b(?:sk-(?:ant-|proj-)?[A-Za-z0-9_-]{20,}|AIza[0-9A-Za-z_-]{35}|[rs]k_live_[A-Za-z0-9]{20,}|xox[abprs]-[A-Za-z0-9-]{10,})`)},
+	{"jwt", regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{10,}\.eyJ[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]{10,}`)},
+	{"url-credentials", regexp.MustCompile(`(?i)\b[a-z][a-z0-9+.-]*://[^\s:/@]+:(?P<secret>[^\s:/@]+)@`)},
+	// password = "..." in code, and KEY=value lines in .env, YAML and properties files
+	{"password", regexp.MustCompile(`(?i)` + secretKey + `["']?\s*:?=?\s*["'](?P<secret>[^"'\s]{4,})["']`)},
+	{"password", regexp.MustCompile(`(?im)^[+-]?\s*(?:export\s+)?[A-Za-z0-9_.-]*` + secretKey + `[A-Za-z0-9_]*\s*[:=]\s*(?P<secret>[^\s"'#]{4,})\s*$`)},
+}
+
+// secretKey matches the names of settings that usually hold credentials
+const secretKey = `(?:password|passwd|pwd|secret|api[_-]?key|access[_-]?key|auth[_-]?token|client[_-]?secret)`
+
+// highEntropyToken matches candidates for the entropy check: long runs of
+// base64/hex-like characters that no specific rule recognized
+var highEntropyToken = regexp.MustCompile(`[A-Za-z0-9+/_=-]{32,}`)
+
+// minEntropy is the Shannon entropy, in bits per character, above which a
+// token is treated as a secret. Hex strings such as commit hashes stay below it.
+const minEntropy = 4.2
+
+// Redactor replaces secrets with "[REDACTED:<rule>]" placeholders
+type Redactor struct {
+	rules []Rule
+}
+
+// New returns a Redactor using DefaultRules plus the given user-defined regexes
+func New(custom []string) (*Redactor, error) {
+	rules := append([]Rule{}, DefaultRules...)
+	for i, pattern := range custom {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redact pattern '%s': %w", pattern, err)
+		}
+		rules = append(rules, Rule{Name: fmt.Sprintf("custom-%d", i+1), Pattern: re})
+	}
+	return &Redactor{rules: rules}, nil
+}
+
+// Report counts redactions per rule
+type Report map[string]int
+
+// Total returns the number of redactions made
+func (r Report) Total() int {
+	total := 0
+	for _, n := range r {
+		total += n
+	}
+	return total
+}
+
+// String lists the counts per rule, e.g. "aws-access-key: 1, password: 2"
+func (r Report) String() string {
+	names := make([]string, 0, len(r))
+	for name := range r {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = fmt.Sprintf("%s: %d", name, r[name])
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Text returns text with every secret replaced, recording counts in report
+func (r *Redactor) Text(text string, report Report) string {
+	for _, rule := range r.rules {
+		text = replace(rule, text, report)
+	}
+	return highEntropyToken.ReplaceAllStringFunc(text, func(token string) string {
+		if strings.HasPrefix(token, "[REDACTED") || !looksRandom(token) {
+			return token
+		}
+		report["high-entropy"]++
+		return placeholder("high-entropy")
+	})
+}
+
+// Commits redacts the messages and code changes of commits in place. The
+// Conventional Commits fields are parsed again from the redacted message.
+func (r *Redactor) Commits(commits []types.CommitData) Report {
+	report := Report{}
+	for i := range commits {
+		commits[i].Message = r.Text(commits[i].Message, report)
+		if commits[i].Conventional != nil {
+			commits[i].Conventional = conventional.Parse(commits[i].Message)
+		}
+		for j := range commits[i].Files {
+			if content := commits[i].Files[j].Content; content != "" {
+				commits[i].Files[j].Content = r.Text(content, report)
+			}
+		}
+	}
+	return report
+}
+
+func replace(rule Rule, text string, report Report) string {
+	group := rule.Pattern.SubexpIndex("secret")
+	if group < 0 {
+		return rule.Pattern.ReplaceAllStringFunc(text, func(string) string {
+			report[rule.Name]++
+			return placeholder(rule.Name)
+		})
+	}
+
+	// Replace only the secret group, keeping the surrounding key and quotes
+	var out strings.Builder
+	last := 0
+	for _, match := range rule.Pattern.FindAllStringSubmatchIndex(text, -1) {
+		start, end := match[2*group], match[2*group+1]
+		if start < 0 || strings.HasPrefix(text[start:end], "[REDACTED") {
+			continue
+		}
+		out.WriteString(text[last:start])
+		out.WriteString(placeholder(rule.Name))
+		last = end
+		report[rule.Name]++
+	}
+	out.WriteString(text[last:])
+	return out.String()
+}
+
+func placeholder(name string) string {
+	return "[REDACTED:" + name + "]"
+}
+
+// looksRandom reports whether token mixes letter cases and digits with high
+// entropy, the shape of generated keys rather than identifiers or hashes
+func looksRandom(token string) bool {
+	var upper, lower, digit bool
+	for _, c := range token {
+		switch {
+		case c >= 'A' && c <= 'Z':
+			upper = true
+		case c >= 'a' && c <= 'z':
+			lower = true
+		case c >= '0' && c <= '9':
+			digit = true
+		}
+	}
+	return upper && lower && digit && entropy(token) > minEntropy
+}
+
+// entropy returns the Shannon entropy of s in bits per character
+func entropy(s string) float64 {
+	counts := map[rune]int{}
+	for _, c := range s {
+		counts[c]++
+	}
+	var bits float64
+	n := float64(len(s))
+	for _, count := range counts {
+		p := float64(count) / n
+		bits -= p * math.Log2(p)
+	}
+	return bits
+}
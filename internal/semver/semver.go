@@ -0,0 +1,204 @@
+// Package semver parses, compares and bumps Semantic Versions
+// (https://semver.org), including pre-release channels such as 1.3.0-rc.2.
+package semver
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Version is a semantic version. Its tag may carry a "v" prefix, which String leaves out.
+type Version struct {
+	Major, Minor, Patch uint64
+	// Prerelease holds the dot-separated identifiers after "-", e.g. ["rc", "2"]
+	Prerelease []string
+	Build      string
+}
+
+// pattern is the semver 2.0.0 grammar, with an optional "v" prefix
+var pattern = regexp.MustCompile(`^[vV]?(0|[1-9][0-9]*)\.(0|[1-9][0-9]*)\.(0|[1-9][0-9]*)` +
+	`(?:-((?:0|[1-9][0-9]*|[0-9]*[A-Za-z-][0-9A-Za-z-]*)(?:\.(?:0|[1-9][0-9]*|[0-9]*[A-Za-z-][0-9A-Za-z-]*))*))?` +
+	`(?:\+([0-9A-Za-z-]+(?:\.[0-9A-Za-z-]+)*))?$`)
+
+// Parse parses a version such as 1.2.3, v1.3.0-rc.1 or 2.0.0+build.5
+func Parse(s string) (Version, error) {
+	m := pattern.FindStringSubmatch(strings.TrimSpace(s))
+	if m == nil {
+		return Version{}, fmt.Errorf("invalid semantic version %q", s)
+	}
+	var v Version
+	var err error
+	for i, field := range []*uint64{&v.Major, &v.Minor, &v.Patch} {
+		if *field, err = strconv.ParseUint(m[i+1], 10, 64); err != nil {
+			return Version{}, fmt.Errorf("invalid semantic version %q: %w", s, err)
+		}
+	}
+	if m[4] != "" {
+		v.Prerelease = strings.Split(m[4], ".")
+	}
+	v.Build = m[5]
+	return v, nil
+}
+
+// String formats the version without a "v" prefix
+func (v Version) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if len(v.Prerelease) > 0 {
+		s += "-" + strings.Join(v.Prerelease, ".")
+	}
+	if v.Build != "" {
+		s += "+" + v.Build
+	}
+	return s
+}
+
+// IsPrerelease reports whether v is a pre-release such as 1.3.0-rc.1
+func (v Version) IsPrerelease() bool {
+	return len(v.Prerelease) > 0
+}
+
+// Release returns v without its pre-release and build metadata
+func (v Version) Release() Version {
+	return Version{Major: v.Major, Minor: v.Minor, Patch: v.Patch}
+}
+
+// Compare returns -1, 0 or 1 as a has lower, equal or higher precedence than
+// b. Build metadata is ignored and a pre-release precedes its release.
+func Compare(a, b Version) int {
+	for _, pair := range [][2]uint64{{a.Major, b.Major}, {a.Minor, b.Minor}, {a.Patch, b.Patch}} {
+		if pair[0] != pair[1] {
+			if pair[0] < pair[1] {
+				return -1
+			}
+			return 1
+		}
+	}
+	switch {
+	case len(a.Prerelease) == 0 && len(b.Prerelease) == 0:
+		return 0
+	case len(a.Prerelease) == 0:
+		return 1
+	case len(b.Prerelease) == 0:
+		return -1
+	}
+	for i := 0; i < len(a.Prerelease) && i < len(b.Prerelease); i++ {
+		if c := compareIdentifier(a.Prerelease[i], b.Prerelease[i]); c != 0 {
+			return c
+		}
+	}
+	switch {
+	case len(a.Prerelease) < len(b.Prerelease):
+		return -1
+	case len(a.Prerelease) > len(b.Prerelease):
+		return 1
+	}
+	return 0
+}
+
+// compareIdentifier compares pre-release identifiers: numbers numerically and
+// before any alphanumeric identifier, which compare in ASCII order
+func compareIdentifier(a, b string) int {
+	na, aErr := strconv.ParseUint(a, 10, 64)
+	nb, bErr := strconv.ParseUint(b, 10, 64)
+	switch {
+	case aErr == nil && bErr == nil:
+		if na == nb {
+			return 0
+		}
+		if na < nb {
+			return -1
+		}
+		return 1
+	case aErr == nil:
+		return -1
+	case bErr == nil:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+// Level is the size of a version bump
+type Level int
+
+const (
+	None Level = iota
+	Patch
+	Minor
+	Major
+)
+
+func (l Level) String() string {
+	switch l {
+	case Patch:
+		return "patch"
+	case Minor:
+		return "minor"
+	case Major:
+		return "major"
+	default:
+		return "none"
+	}
+}
+
+// ParseLevel parses "major", "minor", "patch" or "none"
+func ParseLevel(s string) (Level, error) {
+	for _, level := range []Level{None, Patch, Minor, Major} {
+		if strings.EqualFold(strings.TrimSpace(s), level.String()) {
+			return level, nil
+		}
+	}
+	return None, fmt.Errorf("invalid version bump %q, expected major, minor, patch or none", s)
+}
+
+// Bump returns the release after v's release for a change of the given level
+func (v Version) Bump(level Level) Version {
+	next := v.Release()
+	switch level {
+	case Major:
+		next = Version{Major: next.Major + 1}
+	case Minor:
+		next = Version{Major: next.Major, Minor: next.Minor + 1}
+	case Patch:
+		next.Patch++
+	}
+	return next
+}
+
+// Effective returns the level a bump has on v: while the major version is
+// zero, breaking changes bump the minor version
+func (v Version) Effective(level Level) Level {
+	if v.Major == 0 && level == Major {
+		return Minor
+	}
+	return level
+}
+
+// Next returns the version to release after stable, the latest release (the
+// zero Version when there is none), given the level of the changes since
+// then. latest is the latest version including pre-releases.
+//
+// The bump is stable.Effective(level), and a release never goes below a
+// pre-release that was already tagged. With a channel such as "rc", Next
+// returns a pre-release: the next one on latest's channel when latest is a
+// pre-release of the same version, else "-rc.1".
+func Next(stable, latest Version, level Level, channel string) Version {
+	next := stable.Bump(stable.Effective(level))
+	if latest.IsPrerelease() && Compare(latest.Release(), next) > 0 {
+		next = latest.Release()
+	}
+	if channel == "" {
+		return next
+	}
+
+	number := uint64(1)
+	if latest.IsPrerelease() && Compare(latest.Release(), next) == 0 &&
+		len(latest.Prerelease) == 2 && latest.Prerelease[0] == channel {
+		if n, err := strconv.ParseUint(latest.Prerelease[1], 10, 64); err == nil {
+			number = n + 1
+		}
+	}
+	next.Prerelease = []string{channel, strconv.FormatUint(number, 10)}
+	return next
+}
@@ -0,0 +1,88 @@
+package semver
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	v, err := Parse("v1.3.0-rc.2+build.7")
+	require.NoError(t, err)
+	assert.Equal(t, Version{Major: 1, Minor: 3, Prerelease: []string{"rc", "2"}, Build: "build.7"}, v)
+	assert.Equal(t, "1.3.0-rc.2+build.7", v.String())
+	assert.True(t, v.IsPrerelease())
+	assert.Equal(t, "1.3.0", v.Release().String())
+
+	for _, invalid := range []string{"1.2", "01.2.3", "1.2.3-", "1.2.3-01", "release-1", "v"} {
+		_, err := Parse(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	// The precedence example from the semver spec, shuffled
+	ordered := []string{"1.0.0-alpha", "1.0.0-alpha.1", "1.0.0-alpha.beta", "1.0.0-beta", "1.0.0-beta.2", "1.0.0-beta.11", "1.0.0-rc.1", "1.0.0", "1.0.1", "1.10.0", "2.0.0"}
+	versions := make([]Version, len(ordered))
+	for i, j := range []int{8, 3, 10, 0, 6, 1, 9, 4, 7, 2, 5} {
+		v, err := Parse(ordered[j])
+		require.NoError(t, err)
+		versions[i] = v
+	}
+	sort.Slice(versions, func(i, j int) bool { return Compare(versions[i], versions[j]) < 0 })
+
+	var got []string
+	for _, v := range versions {
+		got = append(got, v.String())
+	}
+	assert.Equal(t, ordered, got)
+	assert.Equal(t, 0, Compare(Version{Major: 1, Build: "a"}, Version{Major: 1, Build: "b"}))
+}
+
+func TestNext(t *testing.T) {
+	parse := func(s string) Version {
+		v, err := Parse(s)
+		require.NoError(t, err)
+		return v
+	}
+	tests := []struct {
+		stable, latest string
+		level          Level
+		channel        string
+		expected       string
+	}{
+		{"1.2.3", "1.2.3", Patch, "", "1.2.4"},
+		{"1.2.3", "1.2.3", Minor, "", "1.3.0"},
+		{"1.2.3", "1.2.3", Major, "", "2.0.0"},
+		{"0.4.1", "0.4.1", Major, "", "0.5.0"},
+		{"0.0.0", "0.0.0", Minor, "", "0.1.0"},
+		// Pre-release channels
+		{"1.2.3", "1.2.3", Minor, "rc", "1.3.0-rc.1"},
+		{"1.2.3", "1.3.0-rc.1", Minor, "rc", "1.3.0-rc.2"},
+		{"1.2.3", "1.3.0-beta.4", Minor, "rc", "1.3.0-rc.1"},
+		{"1.2.3", "1.3.0-rc.9", Major, "rc", "2.0.0-rc.1"},
+		// Releasing a pre-release, never below it
+		{"1.2.3", "1.3.0-rc.2", Minor, "", "1.3.0"},
+		{"1.2.3", "1.3.0-rc.2", Patch, "", "1.3.0"},
+	}
+	for _, tt := range tests {
+		next := Next(parse(tt.stable), parse(tt.latest), tt.level, tt.channel)
+		assert.Equal(t, tt.expected, next.String(), "%s / %s %s %s", tt.stable, tt.latest, tt.level, tt.channel)
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	level, err := ParseLevel(" Minor ")
+	require.NoError(t, err)
+	assert.Equal(t, Minor, level)
+	_, err = ParseLevel("huge")
+	assert.Error(t, err)
+}
+
+func TestEffective(t *testing.T) {
+	assert.Equal(t, Minor, Version{Minor: 4}.Effective(Major))
+	assert.Equal(t, Patch, Version{Minor: 4}.Effective(Patch))
+	assert.Equal(t, Major, Version{Major: 1}.Effective(Major))
+}
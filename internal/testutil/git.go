@@ -36,6 +36,20 @@ func (tr *TestRepo) Cleanup() {
 }
 
 func (tr *TestRepo) AddCommit(t *testing.T, filename, content, message string) {
+	tr.AddCommitAt(t, filename, content, message, time.Now())
+}
+
+// AddCommitAt creates a commit whose author and committer dates are `when`
+func (tr *TestRepo) AddCommitAt(t *testing.T, filename, content, message string, when time.Time) {
+	tr.AddCommitAs(t, filename, content, message, &object.Signature{
+		Name:  "Test User",
+		Email: "test@example.com",
+		When:  when,
+	})
+}
+
+// AddCommitAs creates a commit authored and committed by the given signature
+func (tr *TestRepo) AddCommitAs(t *testing.T, filename, content, message string, signature *object.Signature) {
 	worktree, err := tr.Repo.Worktree()
 	require.NoError(t, err)
 
@@ -46,18 +60,26 @@ func (tr *TestRepo) AddCommit(t *testing.T, filename, content, message string) {
 	_, err = worktree.Add(filename)
 	require.NoError(t, err)
 
-	signature := &object.Signature{
-		Name:  "Test User",
-		Email: "test@example.com",
-		When:  time.Now(),
-	}
-
 	_, err = worktree.Commit(message, &git.CommitOptions{
 		Author: signature,
 	})
 	require.NoError(t, err)
 }
 
+// MoveFile renames a tracked file and commits the move
+func (tr *TestRepo) MoveFile(t *testing.T, from, to, message string) {
+	worktree, err := tr.Repo.Worktree()
+	require.NoError(t, err)
+
+	_, err = worktree.Move(from, to)
+	require.NoError(t, err)
+
+	_, err = worktree.Commit(message, &git.CommitOptions{
+		Author: &object.Signature{Name: "Test User", Email: "test@example.com", When: time.Now()},
+	})
+	require.NoError(t, err)
+}
+
 func (tr *TestRepo) AddMultipleCommits(t *testing.T) {
 	commits := []struct {
 		filename string
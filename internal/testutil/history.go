@@ -0,0 +1,151 @@
+package testutil
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	commitgraph "github.com/go-git/go-git/v5/plumbing/format/commitgraph/v2"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/storage/memory"
+	"github.com/stretchr/testify/require"
+)
+
+// CommitWithParents writes a commit with an empty tree and the given parents
+// straight to the object store, for building histories the worktree can't,
+// such as criss-cross merges. It returns the new commit's hash.
+func CommitWithParents(tb testing.TB, repo *git.Repository, message string, when time.Time, parents ...plumbing.Hash) plumbing.Hash {
+	tb.Helper()
+	tree := &object.Tree{}
+	treeObj := repo.Storer.NewEncodedObject()
+	require.NoError(tb, tree.Encode(treeObj))
+	treeHash, err := repo.Storer.SetEncodedObject(treeObj)
+	require.NoError(tb, err)
+
+	signature := object.Signature{Name: "Test User", Email: "test@example.com", When: when}
+	commit := &object.Commit{
+		Author:       signature,
+		Committer:    signature,
+		Message:      message,
+		TreeHash:     treeHash,
+		ParentHashes: parents,
+	}
+	obj := repo.Storer.NewEncodedObject()
+	require.NoError(tb, commit.Encode(obj))
+	hash, err := repo.Storer.SetEncodedObject(obj)
+	require.NoError(tb, err)
+	return hash
+}
+
+// SyntheticHistory is a large in-memory history for benchmarks
+type SyntheticHistory struct {
+	Repo *git.Repository
+	// Main and Feature are branch tips whose merge base is Fork
+	Main, Feature, Fork plumbing.Hash
+	// Graph is a commit-graph index of every commit
+	Graph *commitgraph.MemoryIndex
+}
+
+// CreateSyntheticHistory builds a main branch of about n commits, with a
+// three-commit side branch merged every 50 commits, and a 20-commit feature
+// branch forked 100 commits below the tip of main
+func CreateSyntheticHistory(tb testing.TB, n int) *SyntheticHistory {
+	tb.Helper()
+	repo, err := git.Init(memory.NewStorage(), nil)
+	require.NoError(tb, err)
+
+	h := &SyntheticHistory{Repo: repo}
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	count := 0
+	commit := func(message string, parents ...plumbing.Hash) plumbing.Hash {
+		count++
+		return CommitWithParents(tb, repo, message, start.Add(time.Duration(count)*time.Minute), parents...)
+	}
+
+	tip := commit("root")
+	for i := 1; count < n; i++ {
+		if i%50 == 0 {
+			side := tip
+			for j := 0; j < 3; j++ {
+				side = commit(fmt.Sprintf("side %d.%d", i, j), side)
+			}
+			tip = commit(fmt.Sprintf("merge side %d", i), tip, side)
+		} else {
+			tip = commit(fmt.Sprintf("main %d", i), tip)
+		}
+		if h.Fork.IsZero() && count >= n-100 {
+			h.Fork = tip
+		}
+	}
+	h.Main = tip
+
+	feature := h.Fork
+	for i := 0; i < 20; i++ {
+		feature = commit(fmt.Sprintf("feature %d", i), feature)
+	}
+	h.Feature = feature
+
+	h.Graph = CommitGraph(tb, repo, h.Main, h.Feature)
+	return h
+}
+
+// CommitGraph builds a commit-graph index, with generation numbers, of every
+// commit reachable from tips
+func CommitGraph(tb testing.TB, repo *git.Repository, tips ...plumbing.Hash) *commitgraph.MemoryIndex {
+	tb.Helper()
+	index := commitgraph.NewMemoryIndex()
+	generations := map[plumbing.Hash]uint64{}
+
+	// Add parents before children, so every parent's generation is known
+	for _, tip := range tips {
+		stack := []plumbing.Hash{tip}
+		for len(stack) > 0 {
+			top := stack[len(stack)-1]
+			if _, done := generations[top]; done {
+				stack = stack[:len(stack)-1]
+				continue
+			}
+			commit, err := repo.CommitObject(top)
+			require.NoError(tb, err)
+
+			pending := false
+			generation := uint64(1)
+			for _, parent := range commit.ParentHashes {
+				if parentGen, done := generations[parent]; done {
+					generation = max(generation, parentGen+1)
+				} else {
+					stack = append(stack, parent)
+					pending = true
+				}
+			}
+			if pending {
+				continue
+			}
+			stack = stack[:len(stack)-1]
+			generations[top] = generation
+			index.Add(top, &commitgraph.CommitData{
+				TreeHash:     commit.TreeHash,
+				ParentHashes: commit.ParentHashes,
+				Generation:   generation,
+				When:         commit.Committer.When,
+			})
+		}
+	}
+	return index
+}
+
+// WriteCommitGraph writes .git/objects/info/commit-graph covering every
+// commit reachable from tips
+func (tr *TestRepo) WriteCommitGraph(tb testing.TB, tips ...plumbing.Hash) {
+	tb.Helper()
+	path := filepath.Join(tr.Dir, ".git", "objects", "info", "commit-graph")
+	require.NoError(tb, os.MkdirAll(filepath.Dir(path), 0755))
+	file, err := os.Create(path)
+	require.NoError(tb, err)
+	defer file.Close()
+	require.NoError(tb, commitgraph.NewEncoder(file).Encode(CommitGraph(tb, tr.Repo, tips...)))
+}
@@ -10,13 +10,48 @@ type CommitStats struct {
 	PrimaryLang string   `json:"primary_lang"`
 }
 
+// Statuses of moved files; other files keep go-git's Insert, Delete and Modify
+const (
+	StatusRenamed = "Rename"
+	StatusCopied  = "Copy"
+)
+
 // FileChange represents a file modification in a commit
 type FileChange struct {
-	Path      string `json:"path"`
-	Status    string `json:"status"`
+	Path   string `json:"path"`
+	Status string `json:"status"`
+	// OldPath is the source of a renamed or copied file
+	OldPath   string `json:"old_path,omitempty"`
 	Content   string `json:"content,omitempty"`
 	Additions int    `json:"additions"`
 	Deletions int    `json:"deletions"`
+	// Excluded says why Content was left out (lockfile, vendored, generated, ...)
+	Excluded string `json:"excluded,omitempty"`
+	// Binary and Truncated files carry their sizes in bytes instead of content;
+	// Truncated ones were too large to diff, so their line counts are zero
+	Binary    bool  `json:"binary,omitempty"`
+	Truncated bool  `json:"truncated,omitempty"`
+	OldSize   int64 `json:"old_size,omitempty"`
+	NewSize   int64 `json:"new_size,omitempty"`
+}
+
+// ConventionalCommit is a commit message that follows the Conventional Commits spec
+type ConventionalCommit struct {
+	Type  string `json:"type"`
+	Scope string `json:"scope,omitempty"`
+	// Breaking is set by a "!" after the type/scope or a BREAKING CHANGE footer
+	Breaking bool `json:"breaking,omitempty"`
+	// BreakingNote is the BREAKING CHANGE footer, empty when only "!" marks the change
+	BreakingNote string    `json:"breaking_note,omitempty"`
+	Subject      string    `json:"subject"`
+	Body         string    `json:"body,omitempty"`
+	Trailers     []Trailer `json:"trailers,omitempty"`
+}
+
+// Trailer is a "Key: value" footer such as Refs or Signed-off-by
+type Trailer struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
 }
 
 // CommitData represents standardized commit information for AI consumption
@@ -27,4 +62,6 @@ type CommitData struct {
 	Date    string       `json:"date"`
 	Stats   CommitStats  `json:"stats"`
 	Files   []FileChange `json:"files"`
+	// Conventional is the parsed message, nil when it doesn't follow Conventional Commits
+	Conventional *ConventionalCommit `json:"conventional,omitempty"`
 }